@@ -0,0 +1,84 @@
+package alien_test
+
+import (
+	"fmt"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetrackingReusesLinks(t *testing.T) {
+	skipWhenDebugging(t)
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	useA := alien.Signal(rs, true)
+	a := alien.Signal(rs, 1)
+	b := alien.Signal(rs, 2)
+	// Switching branches drops one link and creates another every time.
+	c := alien.Computed(rs, func(oldValue int) int {
+		if useA.Value() {
+			return a.Value()
+		}
+		return b.Value()
+	})
+	c.Value()
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		useA.SetValue(!useA.Value())
+		c.Value()
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestRetrackingManyDepsReusesLinks(t *testing.T) {
+	skipWhenDebugging(t)
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	offset := alien.Signal(rs, 0)
+	sources := make([]*alien.WriteableSignal[int], 64)
+	for i := range sources {
+		sources[i] = alien.Signal(rs, i)
+	}
+	// Reads a sliding window of sources, so half the links change each time.
+	window := alien.Computed(rs, func(oldValue int) int {
+		sum := 0
+		for i := range len(sources) / 2 {
+			sum += sources[(offset.Value()+i)%len(sources)].Value()
+		}
+		return sum
+	})
+	window.Value()
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		offset.SetValue((offset.Value() + len(sources)/4) % len(sources))
+		window.Value()
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestPropagateAllocatesNothingOnceWarm(t *testing.T) {
+	skipWhenDebugging(t)
+	for _, shape := range [][2]int{{1, 1}, {1, 100}, {100, 1}, {10, 10}, {100, 100}} {
+		w, h := shape[0], shape[1]
+		t.Run(fmt.Sprintf("%dx%d", w, h), func(t *testing.T) {
+			src := buildPropagate(t, w, h)
+			src.SetValue(0)
+
+			next := 1
+			allocs := testing.AllocsPerRun(100, func() {
+				src.SetValue(next)
+				next++
+			})
+			assert.Zero(t, allocs)
+		})
+	}
+}
+
+func skipWhenDebugging(t *testing.T) {
+	if debugBuild {
+		t.Skip("the alien_debug invariant checker allocates and walks whole chains")
+	}
+}
@@ -0,0 +1,358 @@
+package alien_test
+
+import (
+	"fmt"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+)
+
+// buildPropagate builds the "propagate: W * H" shape of cmd/benchmark: w
+// chains of h computeds hanging off one source, each read by an effect.
+func buildPropagate(tb testing.TB, w, h int) *alien.WriteableSignal[int] {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		tb.Fatal(err)
+	})
+	src := alien.Signal(rs, 1)
+	for range w {
+		var last alien.Readable[int] = src
+		for range h {
+			prev := last
+			last = alien.Computed(rs, func(oldValue int) int {
+				return prev.Value() + 1
+			})
+		}
+		alien.Effect(rs, func() error {
+			last.Value()
+			return nil
+		})
+	}
+	return src
+}
+
+func BenchmarkPropagate(b *testing.B) {
+	for _, w := range []int{1, 10, 100} {
+		for _, h := range []int{1, 10, 100} {
+			b.Run(fmt.Sprintf("%dx%d", w, h), func(b *testing.B) {
+				src := buildPropagate(b, w, h)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := range b.N {
+					src.SetValue(i + 2)
+				}
+			})
+		}
+	}
+}
+
+// The topologies below follow js-reactivity-benchmark's kairo and cellx
+// suites.
+
+func newBenchSystem(b *testing.B) *alien.ReactiveSystem {
+	return alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		b.Fatal(err)
+	})
+}
+
+func BenchmarkDeep(b *testing.B) {
+	rs := newBenchSystem(b)
+	head := alien.Signal(rs, 0)
+	var current alien.Readable[int] = head
+	for range 50 {
+		prev := current
+		current = alien.Computed(rs, func(oldValue int) int {
+			return prev.Value() + 1
+		})
+	}
+	runs := 0
+	alien.Effect(rs, func() error {
+		current.Value()
+		runs++
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		head.SetValue(i + 1)
+	}
+	if runs != b.N+1 {
+		b.Fatalf("effect ran %d times", runs)
+	}
+}
+
+func BenchmarkBroad(b *testing.B) {
+	rs := newBenchSystem(b)
+	head := alien.Signal(rs, 0)
+	runs := 0
+	for i := range 50 {
+		current := alien.Computed(rs, func(oldValue int) int {
+			return head.Value() + i
+		})
+		current2 := alien.Computed(rs, func(oldValue int) int {
+			return current.Value() + 1
+		})
+		alien.Effect(rs, func() error {
+			current2.Value()
+			runs++
+			return nil
+		})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		head.SetValue(i + 1)
+	}
+	if runs != 50*(b.N+1) {
+		b.Fatalf("effects ran %d times", runs)
+	}
+}
+
+func BenchmarkDiamond(b *testing.B) {
+	rs := newBenchSystem(b)
+	head := alien.Signal(rs, 0)
+	current := make([]*alien.ReadonlySignal[int], 5)
+	for i := range current {
+		current[i] = alien.Computed(rs, func(oldValue int) int {
+			return head.Value() + 1
+		})
+	}
+	sum := alien.Computed(rs, func(oldValue int) int {
+		total := 0
+		for _, c := range current {
+			total += c.Value()
+		}
+		return total
+	})
+	runs := 0
+	alien.Effect(rs, func() error {
+		sum.Value()
+		runs++
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		head.SetValue(i + 1)
+	}
+	if runs != b.N+1 {
+		b.Fatalf("effect ran %d times", runs)
+	}
+}
+
+func BenchmarkTriangle(b *testing.B) {
+	rs := newBenchSystem(b)
+	head := alien.Signal(rs, 0)
+	var current alien.Readable[int] = head
+	list := make([]alien.Readable[int], 0, 10)
+	for range 10 {
+		prev := current
+		list = append(list, prev)
+		current = alien.Computed(rs, func(oldValue int) int {
+			return prev.Value() + 1
+		})
+	}
+	sum := alien.Computed(rs, func(oldValue int) int {
+		total := 0
+		for _, c := range list {
+			total += c.Value()
+		}
+		return total
+	})
+	runs := 0
+	alien.Effect(rs, func() error {
+		sum.Value()
+		runs++
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		head.SetValue(i + 1)
+	}
+	if runs != b.N+1 {
+		b.Fatalf("effect ran %d times", runs)
+	}
+}
+
+func BenchmarkMux(b *testing.B) {
+	rs := newBenchSystem(b)
+	const n = 100
+	heads := make([]*alien.WriteableSignal[int], n)
+	for i := range heads {
+		heads[i] = alien.Signal(rs, 0)
+	}
+	mux := alien.Computed(rs, func(oldValue [n]int) [n]int {
+		var all [n]int
+		for i, h := range heads {
+			all[i] = h.Value()
+		}
+		return all
+	})
+	for i := range n {
+		split := alien.Computed(rs, func(oldValue int) int {
+			return mux.Value()[i]
+		})
+		plusOne := alien.Computed(rs, func(oldValue int) int {
+			return split.Value() + 1
+		})
+		alien.Effect(rs, func() error {
+			plusOne.Value()
+			return nil
+		})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		heads[i%n].SetValue(i + 1)
+	}
+}
+
+func BenchmarkRepeatedObservers(b *testing.B) {
+	rs := newBenchSystem(b)
+	head := alien.Signal(rs, 0)
+	current := alien.Computed(rs, func(oldValue int) int {
+		total := 0
+		for range 30 {
+			total += head.Value()
+		}
+		return total
+	})
+	runs := 0
+	alien.Effect(rs, func() error {
+		current.Value()
+		runs++
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		head.SetValue(i + 1)
+	}
+	if runs != b.N+1 {
+		b.Fatalf("effect ran %d times", runs)
+	}
+}
+
+func BenchmarkUnstable(b *testing.B) {
+	rs := newBenchSystem(b)
+	head := alien.Signal(rs, 0)
+	double := alien.Computed(rs, func(oldValue int) int {
+		return head.Value() * 2
+	})
+	inverse := alien.Computed(rs, func(oldValue int) int {
+		return -head.Value()
+	})
+	// Switches between two sets of dependencies on every write.
+	current := alien.Computed(rs, func(oldValue int) int {
+		result := 0
+		for range 20 {
+			if head.Value()%2 == 1 {
+				result += double.Value()
+			} else {
+				result += inverse.Value()
+			}
+		}
+		return result
+	})
+	runs := 0
+	alien.Effect(rs, func() error {
+		current.Value()
+		runs++
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		head.SetValue(i + 1)
+	}
+	if runs != b.N+1 {
+		b.Fatalf("effect ran %d times", runs)
+	}
+}
+
+type cellxLayer struct {
+	a, b, c, d alien.Readable[int]
+}
+
+func BenchmarkCellx(b *testing.B) {
+	for _, layers := range []int{1000, 5000} {
+		b.Run(fmt.Sprint(layers), func(b *testing.B) {
+			rs := newBenchSystem(b)
+			start := [4]*alien.WriteableSignal[int]{
+				alien.Signal(rs, 1),
+				alien.Signal(rs, 2),
+				alien.Signal(rs, 3),
+				alien.Signal(rs, 4),
+			}
+			layer := cellxLayer{start[0], start[1], start[2], start[3]}
+			for range layers {
+				m := layer
+				layer = cellxLayer{
+					a: alien.Computed(rs, func(oldValue int) int { return m.b.Value() }),
+					b: alien.Computed(rs, func(oldValue int) int { return m.a.Value() - m.c.Value() }),
+					c: alien.Computed(rs, func(oldValue int) int { return m.b.Value() + m.d.Value() }),
+					d: alien.Computed(rs, func(oldValue int) int { return m.c.Value() }),
+				}
+			}
+			end := layer
+			for _, cell := range []alien.Readable[int]{end.a, end.b, end.c, end.d} {
+				alien.Effect(rs, func() error {
+					cell.Value()
+					return nil
+				})
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				rs.Batch(func() {
+					start[0].SetValue(4 + i)
+					start[1].SetValue(3 + i)
+					start[2].SetValue(2 + i)
+					start[3].SetValue(1 + i)
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkParallelRefresh(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+				b.Fatal(err)
+			}, alien.WithWorkers(workers))
+			src := alien.Signal(rs, 0)
+			tails := make([]alien.Readable[int], 64)
+			for i := range tails {
+				var last alien.Readable[int] = src
+				for range 100 {
+					prev := last
+					last = alien.Computed(rs, func(oldValue int) int {
+						return prev.Value() + 1
+					})
+				}
+				tails[i] = last
+			}
+			alien.Effect(rs, func() error {
+				for _, tail := range tails {
+					tail.Value()
+				}
+				return nil
+			}, alien.WithParallel())
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				src.SetValue(i + 1)
+			}
+		})
+	}
+}
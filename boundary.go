@@ -0,0 +1,118 @@
+package alien
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrorAction tells an error boundary what to do with an error it caught.
+type ErrorAction int
+
+const (
+	// RecoverError swallows the error; the failing effect stays alive.
+	RecoverError ErrorAction = iota
+	// StopScope stops the boundary scope and everything it owns.
+	StopScope
+	// RethrowError hands the error to the enclosing boundary, or to the
+	// system's OnErrorFunc if there is none.
+	RethrowError
+)
+
+// ScopeErrorHandler decides how an error boundary reacts to an error or panic
+// raised by any effect or computed it owns.
+type ScopeErrorHandler func(from SignalAware, err error) ErrorAction
+
+// PanicError is what an error boundary receives when an effect, or a
+// computed read by it, panics.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("alien: panic: %v", e.Value)
+}
+
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// EffectScopeWithErrorHandler is an EffectScope that acts as an error
+// boundary: errors returned by, and panics raised in, effects and computeds
+// running under it go to onErr instead of the system's OnErrorFunc.
+func EffectScopeWithErrorHandler(rs *ReactiveSystem, scopedFn ErrFn, onErr ScopeErrorHandler, opts ...Option) (stopScope ErrFn) {
+	e := &EffectRunner{
+		signal: signal{
+			flags: fEffect | fEffectScope,
+		},
+		onErr: onErr,
+	}
+	signal := &e.signal
+	signal.ref = e
+	rs.initNode(signal, opts)
+	rs.adopt(e)
+	if err := rs.runEffectScope(e, signal, scopedFn); err != nil {
+		rs.handleError(e, e, rs.annotate(e, err))
+	}
+	return func() error {
+		rs.stopEffect(e)
+		return nil
+	}
+}
+
+// Runs fn, turning a panic into a *PanicError when a boundary is there to
+// receive it. Without one, panics keep unwinding as they always have.
+func (rs *ReactiveSystem) invoke(e *EffectRunner, fn ErrFn) (err error) {
+	if e.boundary != nil || e.onErr != nil {
+		rs.recovering++
+		defer func() {
+			rs.recovering--
+			if r := recover(); r != nil {
+				if isInvariantViolation(r) {
+					panic(r)
+				}
+				// Panics of parallel workers keep the worker's stack.
+				if wp, ok := r.(*workerPanic); ok {
+					err = &PanicError{Value: wp.value, Stack: wp.stack}
+				} else {
+					err = &PanicError{Value: r, Stack: debug.Stack()}
+				}
+			}
+		}()
+	}
+	return fn()
+}
+
+// Walks the boundaries starting at boundary until one recovers or stops,
+// falling back to the system's OnErrorFunc.
+func (rs *ReactiveSystem) handleError(boundary *EffectRunner, from SignalAware, err error) {
+	for b := boundary; b != nil; b = b.boundary {
+		if b.onErr == nil {
+			continue
+		}
+		switch b.onErr(from, err) {
+		case RecoverError:
+			return
+		case StopScope:
+			// The failing effect, or the flush running it, may still be
+			// walking links that stopping the scope would tear down.
+			rs.whenIdle(func() {
+				rs.stopEffect(b)
+			})
+			return
+		}
+	}
+	if rs.onError != nil {
+		rs.onError(from, err)
+	}
+}
+
+// Returns the boundary that errors raised right now should go to.
+func (rs *ReactiveSystem) currentBoundary() *EffectRunner {
+	owner := rs.currentOwner()
+	if owner == nil || owner.onErr != nil {
+		return owner
+	}
+	return owner.boundary
+}
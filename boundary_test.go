@@ -0,0 +1,206 @@
+package alien_test
+
+import (
+	"errors"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorBoundaryRecover(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, "should be caught by the boundary", err.Error())
+	})
+	count := alien.Signal(rs, 0)
+	errOdd := errors.New("odd")
+
+	var caught []error
+	runs := 0
+	alien.EffectScopeWithErrorHandler(rs, func() error {
+		alien.Effect(rs, func() error {
+			runs++
+			if count.Value()%2 == 1 {
+				return errOdd
+			}
+			return nil
+		})
+		return nil
+	}, func(from alien.SignalAware, err error) alien.ErrorAction {
+		caught = append(caught, err)
+		return alien.RecoverError
+	})
+
+	count.SetValue(1)
+	count.SetValue(2)
+	assert.Equal(t, 3, runs, "the effect survives a recovered error")
+	require.Len(t, caught, 1)
+	assert.ErrorIs(t, caught[0], errOdd)
+}
+
+func TestErrorBoundaryCatchesPanics(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, "should be caught by the boundary", err.Error())
+	})
+	count := alien.Signal(rs, 0)
+	errBroken := errors.New("broken")
+	inverse := alien.Computed(rs, func(oldValue int) int {
+		if count.Value() == 0 {
+			panic(errBroken)
+		}
+		return 100 / count.Value()
+	})
+
+	var caught []error
+	var from []alien.SignalAware
+	alien.EffectScopeWithErrorHandler(rs, func() error {
+		alien.Effect(rs, func() error {
+			inverse.Value()
+			return nil
+		}, alien.WithName("reader"))
+		return nil
+	}, func(f alien.SignalAware, err error) alien.ErrorAction {
+		from = append(from, f)
+		caught = append(caught, err)
+		return alien.RecoverError
+	})
+
+	require.Len(t, caught, 1)
+	var panicErr *alien.PanicError
+	require.ErrorAs(t, caught[0], &panicErr)
+	assert.ErrorIs(t, caught[0], errBroken)
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.Equal(t, "reader", from[0].Name())
+
+	// The graph is still consistent after the panic.
+	count.SetValue(4)
+	assert.Equal(t, 25, inverse.Value())
+	assert.Len(t, caught, 1)
+}
+
+func TestErrorBoundaryStopScope(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	count := alien.Signal(rs, 0)
+
+	healthyRuns, failingRuns := 0, 0
+	alien.EffectScopeWithErrorHandler(rs, func() error {
+		alien.Effect(rs, func() error {
+			healthyRuns++
+			count.Value()
+			return nil
+		})
+		alien.Effect(rs, func() error {
+			failingRuns++
+			if count.Value() > 0 {
+				return errors.New("widget failed")
+			}
+			return nil
+		})
+		return nil
+	}, func(from alien.SignalAware, err error) alien.ErrorAction {
+		return alien.StopScope
+	})
+
+	outsideRuns := 0
+	alien.Effect(rs, func() error {
+		outsideRuns++
+		count.Value()
+		return nil
+	})
+
+	count.SetValue(1)
+	count.SetValue(2)
+	assert.Equal(t, []int{2, 2}, []int{healthyRuns, failingRuns})
+	assert.Equal(t, 3, outsideRuns, "other effects are unaffected")
+}
+
+func TestErrorBoundaryRethrow(t *testing.T) {
+	var global []error
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		global = append(global, err)
+	})
+	errBoom := errors.New("boom")
+
+	var trail []string
+	outer := func(action alien.ErrorAction) alien.ScopeErrorHandler {
+		return func(from alien.SignalAware, err error) alien.ErrorAction {
+			trail = append(trail, "outer")
+			return action
+		}
+	}
+	inner := func(from alien.SignalAware, err error) alien.ErrorAction {
+		trail = append(trail, "inner")
+		return alien.RethrowError
+	}
+
+	alien.EffectScopeWithErrorHandler(rs, func() error {
+		alien.EffectScopeWithErrorHandler(rs, func() error {
+			alien.Effect(rs, func() error {
+				return errBoom
+			})
+			return nil
+		}, inner)
+		return nil
+	}, outer(alien.RecoverError))
+	assert.Equal(t, []string{"inner", "outer"}, trail)
+	assert.Empty(t, global)
+
+	trail = nil
+	alien.EffectScopeWithErrorHandler(rs, func() error {
+		alien.EffectScope(rs, func() error {
+			alien.EffectScopeWithErrorHandler(rs, func() error {
+				return errBoom
+			}, inner)
+			return nil
+		})
+		return nil
+	}, outer(alien.RethrowError))
+	assert.Equal(t, []string{"inner", "outer"}, trail)
+	require.Len(t, global, 1)
+	assert.ErrorIs(t, global[0], errBoom)
+}
+
+func TestErrorBoundaryCatchesPanicsWhileCheckingDeps(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, "should be caught by the boundary", err.Error())
+	})
+	src := alien.Signal(rs, 0)
+	left := alien.Computed(rs, func(oldValue int) int {
+		if src.Value() == 2 {
+			panic("boom")
+		}
+		return src.Value()
+	})
+	right := alien.Computed(rs, func(oldValue int) int {
+		return src.Value() * 10
+	})
+
+	var caught []error
+	var seen [][2]int
+	alien.EffectScopeWithErrorHandler(rs, func() error {
+		alien.Effect(rs, func() error {
+			seen = append(seen, [2]int{left.Value(), right.Value()})
+			return nil
+		}, alien.WithName("reader"))
+		return nil
+	}, func(from alien.SignalAware, err error) alien.ErrorAction {
+		caught = append(caught, err)
+		return alien.RecoverError
+	})
+
+	// The flush finds out whether the effect is dirty by refreshing left,
+	// before the effect itself runs.
+	src.SetValue(1)
+	assert.NotPanics(t, func() { src.SetValue(2) })
+	require.Len(t, caught, 1)
+	var panicErr *alien.PanicError
+	require.ErrorAs(t, caught[0], &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+
+	src.SetValue(3)
+	assert.Equal(t, [][2]int{{0, 0}, {1, 10}, {3, 30}}, seen)
+	assert.Len(t, caught, 1)
+}
@@ -0,0 +1,110 @@
+package alien
+
+// Cause describes the write that made a computed change or an effect re-run.
+type Cause struct {
+	// Signal is the root signal whose SetValue started the propagation.
+	Signal   SignalAware
+	OldValue any
+	NewValue any
+	// Path lists the computeds whose values actually changed on the way from
+	// Signal to the node being inspected, in evaluation order.
+	Path []SignalAware
+}
+
+type causeRecord struct {
+	seq      uint64
+	source   *signal
+	oldValue any
+	newValue any
+	path     []*signal
+}
+
+// WithCausality records, for every computed and effect, which signal write
+// last caused it to change or run. Query it with LastCause.
+func WithCausality() SystemOption {
+	return func(rs *ReactiveSystem) {
+		rs.causality = true
+	}
+}
+
+// LastCause reports the write that last made node change (for computeds) or
+// re-run (for effects). It returns false if causality tracking is disabled or
+// the node hasn't been affected by a write yet.
+func (rs *ReactiveSystem) LastCause(node SignalAware) (Cause, bool) {
+	sig := node.node()
+	if sig.info == nil || sig.info.cause == nil {
+		return Cause{}, false
+	}
+	record := sig.info.cause
+	cause := Cause{
+		Signal:   record.source.public(),
+		OldValue: record.oldValue,
+		NewValue: record.newValue,
+		Path:     make([]SignalAware, len(record.path)),
+	}
+	for i, s := range record.path {
+		cause.Path[i] = s.public()
+	}
+	return cause, true
+}
+
+// ActiveEffect returns the effect currently running, if any. Combined with
+// LastCause it lets an effect ask why it is running.
+func (rs *ReactiveSystem) ActiveEffect() SignalAware {
+	if rs.activeSub == nil || rs.activeSub.flags&fEffect == 0 {
+		return nil
+	}
+	return rs.activeSub.public()
+}
+
+func (rs *ReactiveSystem) recordWrite(source *signal, oldValue, newValue any) {
+	rs.causeSeq++
+	source.info.cause = &causeRecord{
+		seq:      rs.causeSeq,
+		source:   source,
+		oldValue: oldValue,
+		newValue: newValue,
+	}
+}
+
+// Finds the most recent write among the dependencies of sub, scanning up to
+// and including last. Dependencies that changed more recently are closer to
+// the reason sub is being re-evaluated.
+func latestDepCause(sub *signal, last *link) *causeRecord {
+	var latest *causeRecord
+	for link := sub.deps; link != nil; link = link.nextDep {
+		// Inner effects show up as deps of their owner but are never read.
+		info := link.dep.info
+		if link.dep.flags&fEffect == 0 && info != nil && info.cause != nil {
+			if latest == nil || info.cause.seq > latest.seq {
+				latest = info.cause
+			}
+		}
+		if link == last {
+			break
+		}
+	}
+	return latest
+}
+
+func (rs *ReactiveSystem) recordComputedChange(computed *signal) {
+	latest := latestDepCause(computed, computed.depsTail)
+	if latest == nil {
+		return
+	}
+	path := make([]*signal, len(latest.path), len(latest.path)+1)
+	copy(path, latest.path)
+	computed.info.cause = &causeRecord{
+		seq:      latest.seq,
+		source:   latest.source,
+		oldValue: latest.oldValue,
+		newValue: latest.newValue,
+		path:     append(path, computed),
+	}
+}
+
+func (rs *ReactiveSystem) recordEffectCause(effect *signal) {
+	if latest := latestDepCause(effect, nil); latest != nil {
+		effect.info.cause = latest
+	}
+}
@@ -0,0 +1,102 @@
+package alien_test
+
+import (
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastCauseThroughComputedChain(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithCausality())
+
+	qty := alien.Signal(rs, 1, alien.WithName("qty"))
+	unrelated := alien.Signal(rs, "a", alien.WithName("unrelated"))
+	subtotal := alien.Computed(rs, func(oldValue int) int {
+		return qty.Value() * 10
+	}, alien.WithName("subtotal"))
+	total := alien.Computed(rs, func(oldValue int) int {
+		return subtotal.Value() + 5
+	}, alien.WithName("total"))
+
+	var causes []alien.Cause
+	alien.Effect(rs, func() error {
+		total.Value()
+		unrelated.Value()
+		if cause, ok := rs.LastCause(rs.ActiveEffect()); ok {
+			causes = append(causes, cause)
+		}
+		return nil
+	})
+	assert.Empty(t, causes, "first run has no cause")
+
+	qty.SetValue(2)
+	require.Len(t, causes, 1)
+	cause := causes[0]
+	assert.Equal(t, alien.SignalAware(qty), cause.Signal)
+	assert.Equal(t, 1, cause.OldValue)
+	assert.Equal(t, 2, cause.NewValue)
+	assert.Equal(t, []alien.SignalAware{subtotal, total}, cause.Path)
+
+	unrelated.SetValue("b")
+	require.Len(t, causes, 2)
+	cause = causes[1]
+	assert.Equal(t, alien.SignalAware(unrelated), cause.Signal)
+	assert.Empty(t, cause.Path)
+}
+
+func TestLastCauseSkipsUnchangedComputeds(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithCausality())
+
+	a := alien.Signal(rs, 1)
+	b := alien.Signal(rs, 1)
+	isPositive := alien.Computed(rs, func(oldValue bool) bool {
+		return a.Value() > 0
+	})
+	sum := alien.Computed(rs, func(oldValue int) int {
+		return a.Value() + b.Value()
+	})
+
+	runs := 0
+	alien.Effect(rs, func() error {
+		runs++
+		isPositive.Value()
+		sum.Value()
+		return nil
+	})
+
+	rs.Batch(func() {
+		a.SetValue(2)
+		b.SetValue(3)
+	})
+	assert.Equal(t, 2, runs)
+
+	cause, ok := rs.LastCause(sum)
+	require.True(t, ok)
+	assert.Equal(t, alien.SignalAware(b), cause.Signal)
+	assert.Equal(t, []alien.SignalAware{sum}, cause.Path)
+
+	_, ok = rs.LastCause(isPositive)
+	assert.False(t, ok, "isPositive never changed value")
+}
+
+func TestLastCauseDisabled(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	a := alien.Signal(rs, 1)
+	c := alien.Computed(rs, func(oldValue int) int {
+		return a.Value()
+	})
+	c.Value()
+	a.SetValue(2)
+	c.Value()
+
+	_, ok := rs.LastCause(c)
+	assert.False(t, ok)
+}
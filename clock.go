@@ -0,0 +1,37 @@
+package alien
+
+import "time"
+
+// Clock is the source of time for the time based helpers. It is swapped
+// out with WithClock, usually for a FakeClock, so tests don't have to sleep.
+type Clock interface {
+	Now() time.Time
+	// AfterFunc calls f on its own goroutine once d has elapsed, unless the
+	// returned timer is stopped first.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a pending call scheduled with Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the call from happening. It reports false if the call
+	// already happened or the timer was stopped before.
+	Stop() bool
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// WithClock replaces the wall clock used by Debounce, Throttle, Interval,
+// Timeout and Now.
+func WithClock(clock Clock) SystemOption {
+	return func(rs *ReactiveSystem) {
+		rs.clock = clock
+	}
+}
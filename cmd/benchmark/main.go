@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"runtime/pprof"
+	"strconv"
+	"strings"
 	"time"
 
 	alien "github.com/delaneyj/alien-signals-go"
@@ -14,18 +17,98 @@ import (
 )
 
 func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run returns its errors rather than exiting, so that the deferred stop of
+// the CPU profile always gets to flush it.
+func run() error {
+	var (
+		widths     = flag.String("widths", "1,10,100,1000", "comma separated widths: number of chains hanging off the source")
+		heights    = flag.String("heights", "1,10,100,1000", "comma separated heights: number of computeds per chain")
+		iters      = flag.Int("iters", 100, "writes measured per shape")
+		cpuprofile = flag.String("cpuprofile", "", "write a CPU profile to this file, e.g. default.pgo")
+		out        = flag.String("out", "", "write results to this file")
+		format     = flag.String("format", "", "format of -out: json or csv (default: from the file extension, else json)")
+		compare    = flag.String("compare", "", "print per-row deltas against results previously written as JSON")
+	)
 	flag.Parse()
 
-	f, err := os.Create("default.pgo")
+	ww, err := parseInts(*widths)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("-widths: %w", err)
+	}
+	hh, err := parseInts(*heights)
+	if err != nil {
+		return fmt.Errorf("-heights: %w", err)
+	}
+	if *iters < 1 {
+		return errors.New("-iters must be at least 1")
+	}
+	if *out != "" {
+		if _, err := resultsFormat(*out, *format); err != nil {
+			return fmt.Errorf("-format: %w", err)
+		}
+	}
+	var old []Result
+	if *compare != "" {
+		if old, err = readResults(*compare); err != nil {
+			return fmt.Errorf("-compare: %w", err)
+		}
 	}
-	pprof.StartCPUProfile(f)
-	defer pprof.StopCPUProfile()
 
-	ww := []int{1, 10, 100, 1000}
-	hh := []int{1, 10, 100, 1000}
+	if *cpuprofile != "" {
+		f, err := os.Create(*cpuprofile)
+		if err != nil {
+			return err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return err
+		}
+		defer func() {
+			pprof.StopCPUProfile()
+			f.Close()
+		}()
+	}
+
+	var results []Result
+	for _, w := range ww {
+		for _, h := range hh {
+			results = append(results, runPropagate(w, h, *iters))
+		}
+	}
+	printResults(results)
+
+	if *out != "" {
+		if err := writeResults(*out, *format, results); err != nil {
+			return err
+		}
+	}
+	if *compare != "" {
+		printComparison(old, results)
+	}
+	return nil
+}
+
+func parseInts(s string) ([]int, error) {
+	var ints []int
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		if n < 1 {
+			return nil, fmt.Errorf("%d is not positive", n)
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
 
+func runPropagate(w, h, iters int) Result {
 	getValue := func(x any) int {
 		switch x := x.(type) {
 		case *alien.WriteableSignal[int]:
@@ -37,56 +120,82 @@ func main() {
 		}
 	}
 
+	tach := tachymeter.New(&tachymeter.Config{Size: iters})
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		log.Panic(err)
+	})
+	src := alien.Signal(rs, 1)
+	for i := 0; i < w; i++ {
+		var last any
+		last = src
+		for j := 0; j < h; j++ {
+			prev := last
+			last = alien.Computed(rs, func(oldValue int) int {
+				return getValue(prev)
+			})
+		}
+
+		alien.Effect(rs, func() error {
+			getValue(last)
+			return nil
+		})
+	}
+
+	for i := 0; i < iters; i++ {
+		start := time.Now()
+		src.SetValue(src.Value() + 1)
+		tach.AddTime(time.Since(start))
+	}
+
+	calc := tach.Calc()
+	return Result{
+		Name:  fmt.Sprintf("propagate: %d * %d", w, h),
+		Iters: iters,
+		Avg:   calc.Time.Avg,
+		Min:   calc.Time.Min,
+		P75:   calc.Time.P75,
+		P99:   calc.Time.P99,
+		Max:   calc.Time.Max,
+	}
+}
+
+func printResults(results []Result) {
 	tbl := table.NewWriter()
 	tbl.SetOutputMirror(os.Stdout)
 	tbl.AppendHeader(table.Row{"benchmark", "avg", "min", "p75", "p99", "max"})
+	for _, r := range results {
+		tbl.AppendRow(table.Row{r.Name, r.Avg, r.Min, r.P75, r.P99, r.Max})
+	}
+	tbl.Render()
+}
 
-	for _, w := range ww {
-		for _, h := range hh {
-			iters := 100
-			tach := tachymeter.New(&tachymeter.Config{Size: iters})
+func printComparison(old, results []Result) {
+	byName := make(map[string]Result, len(old))
+	for _, r := range old {
+		byName[r.Name] = r
+	}
 
-			// fmt.Sprintf("propagate: %dx%d", w, h), func(b *testing.B) {
-			rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
-				log.Panic(err)
-			})
-			src := alien.Signal(rs, 1)
-			for i := 0; i < w; i++ {
-				var last any
-				last = src
-				for j := 0; j < h; j++ {
-					prev := last
-					last = alien.Computed(rs, func(oldValue int) int {
-						return getValue(prev)
-					})
-				}
-
-				alien.Effect(rs, func() error {
-					getValue(last)
-					return nil
-				})
-
-			}
-
-			for i := 0; i < iters; i++ {
-				start := time.Now()
-				src.SetValue(src.Value() + 1)
-				tach.AddTime(time.Since(start))
-			}
-
-			calc := tach.Calc()
-			tbl.AppendRows([]table.Row{
-				{
-					fmt.Sprintf("propagate: %d * %d", w, h),
-					calc.Time.Avg,
-					calc.Time.Min,
-					calc.Time.P75,
-					calc.Time.P99,
-					calc.Time.Max,
-				},
-			})
+	tbl := table.NewWriter()
+	tbl.SetOutputMirror(os.Stdout)
+	tbl.AppendHeader(table.Row{"benchmark", "old avg", "new avg", "delta", "old p99", "new p99", "delta"})
+	for _, r := range results {
+		prev, ok := byName[r.Name]
+		if !ok {
+			tbl.AppendRow(table.Row{r.Name, "-", r.Avg, "new", "-", r.P99, "new"})
+			continue
 		}
+		tbl.AppendRow(table.Row{
+			r.Name,
+			prev.Avg, r.Avg, delta(prev.Avg, r.Avg),
+			prev.P99, r.P99, delta(prev.P99, r.P99),
+		})
 	}
-
 	tbl.Render()
 }
+
+func delta(old, new time.Duration) string {
+	if old == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", float64(new-old)/float64(old)*100)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Result is one row of output. Durations are written as nanoseconds.
+type Result struct {
+	Name  string        `json:"name"`
+	Iters int           `json:"iters"`
+	Avg   time.Duration `json:"avg_ns"`
+	Min   time.Duration `json:"min_ns"`
+	P75   time.Duration `json:"p75_ns"`
+	P99   time.Duration `json:"p99_ns"`
+	Max   time.Duration `json:"max_ns"`
+}
+
+// resultsFormat picks the format results are written to path in, checking
+// it before anything is run or created.
+func resultsFormat(path, format string) (string, error) {
+	switch format {
+	case "":
+		if filepath.Ext(path) == ".csv" {
+			return "csv", nil
+		}
+		return "json", nil
+	case "json", "csv":
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func writeResults(path, format string, results []Result) error {
+	format, err := resultsFormat(path, format)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if format == "csv" {
+		err = writeCSV(f, results)
+	} else {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(results)
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func writeCSV(f *os.File, results []Result) error {
+	w := csv.NewWriter(f)
+	w.Write([]string{"name", "iters", "avg_ns", "min_ns", "p75_ns", "p99_ns", "max_ns"})
+	for _, r := range results {
+		row := []string{r.Name, strconv.Itoa(r.Iters)}
+		for _, d := range []time.Duration{r.Avg, r.Min, r.P75, r.P99, r.Max} {
+			row = append(row, strconv.FormatInt(int64(d), 10))
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func readResults(path string) ([]Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
@@ -1,39 +1,104 @@
 package alien
 
+import (
+	"runtime"
+	"time"
+	"weak"
+)
+
+// ReadonlySignal is the handle to a computed. The graph only references the
+// computed itself, so with WithWeakComputeds the computed can be unlinked
+// from its dependencies and collected once the handle is unreachable.
 type ReadonlySignal[T comparable] struct {
+	*computed[T]
+}
+
+type computed[T comparable] struct {
 	signal
 
 	rs     *ReactiveSystem
 	value  T
 	getter func(oldValue T) T
+	owner  *EffectRunner
+	// The handle is referenced weakly when computeds may be collected, as
+	// anything else would keep it reachable through the graph.
+	handle     *ReadonlySignal[T]
+	weakHandle weak.Pointer[ReadonlySignal[T]]
 }
 
-func (s *ReadonlySignal[T]) isSignalAware() {}
+func (s *computed[T]) isSignalAware() {}
 
 func (s *ReadonlySignal[T]) Value() T {
+	value, cycle := s.read()
+	if cycle != nil {
+		s.rs.raiseCycle(cycle)
+	}
+	return value
+}
+
+// ValueErr is like Value, but returns a *CycleError instead of reporting it
+// to OnErrorFunc when reading the computed runs into a dependency cycle. The
+// returned value is then the last value computed before the cycle.
+func (s *ReadonlySignal[T]) ValueErr() (T, error) {
+	value, cycle := s.read()
+	if cycle != nil {
+		return value, cycle
+	}
+	return value, nil
+}
+
+func (s *computed[T]) read() (T, *CycleError) {
+	rs := s.rs
+	if rs.parallel {
+		rs.parallelRead(&s.signal)
+		return s.value, nil
+	}
 	flags := s.flags
 	signal := &s.signal
+	if flags&fTracking != 0 {
+		return s.value, rs.newCycleError(signal)
+	}
+
+	var cycle *CycleError
 	if flags&(fDirty|fPendingComputed) != 0 {
-		processComputedUpdate(s.rs, signal, flags)
+		outer := rs.cycle
+		rs.cycle = nil
+		processComputedUpdate(rs, signal, flags)
+		cycle, rs.cycle = rs.cycle, outer
 	}
-	if s.rs.activeSub != nil {
-		s.rs.link(signal, s.rs.activeSub)
-	} else if s.rs.activeScope != nil {
-		s.rs.link(signal, s.rs.activeScope)
+	if rs.activeSub != nil {
+		rs.link(signal, rs.activeSub)
+	} else if rs.activeScope != nil {
+		rs.link(signal, rs.activeScope)
 	}
 
-	return s.value
+	return s.value, cycle
 }
 
-func (s *ReadonlySignal[T]) cas() bool {
+func (s *computed[T]) cas() bool {
 	oldValue := s.value
 	newValue := s.getter(oldValue)
 	s.value = newValue
 	return oldValue != newValue
 }
 
-func Computed[T comparable](rs *ReactiveSystem, getter func(oldValue T) T) *ReadonlySignal[T] {
-	c := &ReadonlySignal[T]{
+// Like cas, for a getter run by a parallel worker. The new value is only
+// kept if the getter read the same deps as on its previous run; otherwise it
+// may have read stale values and has to be evaluated again with tracking.
+func (s *computed[T]) casOn(w *parallelWorker) (changed, kept bool) {
+	oldValue := s.value
+	w.begin(&s.signal)
+	newValue := s.getter(oldValue)
+	if !w.end() {
+		return false, false
+	}
+	s.value = newValue
+	return oldValue != newValue, true
+}
+
+func Computed[T comparable](rs *ReactiveSystem, getter func(oldValue T) T, opts ...Option) *ReadonlySignal[T] {
+	rs.sweepIfDropped()
+	c := &computed[T]{
 		rs:     rs,
 		getter: getter,
 		signal: signal{
@@ -42,24 +107,72 @@ func Computed[T comparable](rs *ReactiveSystem, getter func(oldValue T) T) *Read
 	}
 	signal := &c.signal
 	signal.ref = c
-	return c
+	rs.initNode(signal, opts)
+	if owner := rs.currentOwner(); owner != nil {
+		c.owner = owner
+		owner.owned = append(owner.owned, signal)
+	}
+
+	h := &ReadonlySignal[T]{c}
+	if rs.weakComputeds {
+		c.weakHandle = weak.Make(h)
+		runtime.AddCleanup(h, rs.dropComputed, signal)
+	} else {
+		c.handle = h
+	}
+	return h
+}
+
+func (s *computed[T]) ownedBy() *EffectRunner {
+	return s.owner
+}
+
+// Reports the computed as its handle while that is still reachable, so
+// errors and diagnostics hand out the same value the user holds.
+func (s *computed[T]) public() SignalAware {
+	if s.handle != nil {
+		return s.handle
+	}
+	if h := s.weakHandle.Value(); h != nil {
+		return h
+	}
+	return s
 }
 
 type computedAny interface {
 	cas() (wasDifferent bool)
+	casOn(w *parallelWorker) (changed, kept bool)
+	ownedBy() *EffectRunner
+	public() SignalAware
 }
 
 func updateComputed(rs *ReactiveSystem, signal *signal) bool {
 	prevSub := rs.activeSub
 	rs.activeSub = signal
 	rs.startTracking(signal)
+	rs.computing = append(rs.computing, signal)
 
 	defer func() {
+		rs.computing = rs.computing[:len(rs.computing)-1]
 		rs.activeSub = prevSub
 		rs.endTracking(signal)
 	}()
 
-	return signal.ref.(computedAny).cas()
+	var start time.Time
+	if rs.tracer != nil {
+		start = time.Now()
+	}
+	changed := signal.ref.(computedAny).cas()
+	if rs.metrics != nil {
+		rs.metrics.recordRecompute(signal)
+	}
+	if changed && rs.causality {
+		rs.recordComputedChange(signal)
+	}
+	if rs.tracer != nil {
+		rs.tracer.ComputedRecompute(signal.public(), time.Since(start), changed)
+	}
+	return changed
 }
 
 // Updates the computed subscriber if necessary before its value is accessed.
@@ -71,6 +184,10 @@ func updateComputed(rs *ReactiveSystem, signal *signal) bool {
 // @param computed - The computed subscriber to update.
 // @param flags - The current flag set for this subscriber.
 func processComputedUpdate(rs *ReactiveSystem, signal *signal, flags subscriberFlags) {
+	if flags&fParallel != 0 && rs.workers > 1 {
+		rs.refreshParallel(signal)
+		flags = signal.flags
+	}
 	if flags&fDirty != 0 || rs.checkDirty(signal.deps) {
 		if updateComputed(rs, signal) {
 			subs := signal.subs
@@ -0,0 +1,35 @@
+package alien
+
+// Provide makes value available to Inject calls made by the current effect
+// or scope and everything it owns, directly or through nested scopes. Called
+// outside of any owner it provides an application-wide default. Values are
+// dropped when the owner is stopped or re-runs.
+func Provide(rs *ReactiveSystem, key, value any) {
+	provided := &rs.provided
+	if owner := rs.currentOwner(); owner != nil {
+		provided = &owner.provided
+	}
+	if *provided == nil {
+		*provided = map[any]any{}
+	}
+	(*provided)[key] = value
+}
+
+// Inject looks key up through the owner chain, starting at the current
+// effect or scope and ending at the application-wide values. It reports
+// false if no owner provides key or the closest value is not a T.
+func Inject[T any](rs *ReactiveSystem, key any) (T, bool) {
+	for owner := rs.currentOwner(); owner != nil; owner = owner.parent {
+		if value, ok := owner.provided[key]; ok {
+			typed, ok := value.(T)
+			return typed, ok
+		}
+	}
+	value, ok := rs.provided[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	typed, ok := value.(T)
+	return typed, ok
+}
@@ -0,0 +1,113 @@
+package alien_test
+
+import (
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKey struct{}
+
+func TestProvideInjectThroughScopes(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	count := alien.Signal(rs, 0)
+
+	var seen []string
+	alien.EffectScope(rs, func() error {
+		alien.Provide(rs, tenantKey{}, "acme")
+		alien.EffectScope(rs, func() error {
+			alien.Effect(rs, func() error {
+				count.Value()
+				tenant, ok := alien.Inject[string](rs, tenantKey{})
+				assert.True(t, ok)
+				seen = append(seen, tenant)
+				return nil
+			})
+			return nil
+		})
+
+		alien.EffectScope(rs, func() error {
+			alien.Provide(rs, tenantKey{}, "globex")
+			alien.Effect(rs, func() error {
+				count.Value()
+				tenant, _ := alien.Inject[string](rs, tenantKey{})
+				seen = append(seen, tenant)
+				return nil
+			})
+			return nil
+		})
+		return nil
+	})
+
+	count.SetValue(1)
+	assert.Equal(t, []string{"acme", "globex", "acme", "globex"}, seen)
+}
+
+func TestInjectFromComputed(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+
+	var label *alien.ReadonlySignal[string]
+	alien.EffectScope(rs, func() error {
+		alien.Provide(rs, tenantKey{}, "acme")
+		label = alien.Computed(rs, func(oldValue string) string {
+			tenant, _ := alien.Inject[string](rs, tenantKey{})
+			return "tenant: " + tenant
+		})
+		return nil
+	})
+
+	// Evaluated lazily outside the scope, but still resolves against it.
+	assert.Equal(t, "tenant: acme", label.Value())
+}
+
+func TestInjectDefaultsAndMissingKeys(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	alien.Provide(rs, "retries", 3)
+
+	alien.EffectScope(rs, func() error {
+		retries, ok := alien.Inject[int](rs, "retries")
+		assert.True(t, ok)
+		assert.Equal(t, 3, retries)
+
+		_, ok = alien.Inject[string](rs, "retries")
+		assert.False(t, ok, "wrong type")
+
+		_, ok = alien.Inject[int](rs, "missing")
+		assert.False(t, ok)
+		return nil
+	})
+}
+
+func TestProvidedValuesDroppedOnStop(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	count := alien.Signal(rs, 0)
+
+	var found []bool
+	stop := alien.EffectScope(rs, func() error {
+		alien.Provide(rs, tenantKey{}, "acme")
+		// A root outlives the scope it was created in, but still looks values
+		// up through it.
+		return alien.Root(rs, func(dispose func()) error {
+			alien.Effect(rs, func() error {
+				count.Value()
+				_, ok := alien.Inject[string](rs, tenantKey{})
+				found = append(found, ok)
+				return nil
+			})
+			return nil
+		})
+	})
+
+	stop()
+	count.SetValue(1)
+	assert.Equal(t, []bool{true, false}, found)
+}
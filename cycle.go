@@ -0,0 +1,50 @@
+package alien
+
+import "strings"
+
+// CycleError is returned by ValueErr, or reported to OnErrorFunc by Value,
+// when a computed ends up reading itself while it is being computed.
+type CycleError struct {
+	// Nodes lists the computeds forming the cycle, starting with the one
+	// that was read again. Each node reads the next one, and the last one
+	// reads the first.
+	Nodes []SignalAware
+}
+
+func (e *CycleError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("alien: dependency cycle: ")
+	for _, node := range e.Nodes {
+		sb.WriteString(node.String())
+		sb.WriteString(" -> ")
+	}
+	sb.WriteString(e.Nodes[0].String())
+	return sb.String()
+}
+
+func (rs *ReactiveSystem) newCycleError(target *signal) *CycleError {
+	err := &CycleError{}
+	for i := len(rs.computing) - 1; i >= 0; i-- {
+		if rs.computing[i] == target {
+			for _, s := range rs.computing[i:] {
+				err.Nodes = append(err.Nodes, s.public())
+			}
+			break
+		}
+	}
+	return err
+}
+
+// Surfaces a cycle detected by a read. Inside a computation it is handed to
+// the enclosing read, so it ends up at whoever started the evaluation: their
+// ValueErr returns it, or their Value reports it to the closest error
+// boundary or OnErrorFunc.
+func (rs *ReactiveSystem) raiseCycle(err *CycleError) {
+	if len(rs.computing) > 0 {
+		if rs.cycle == nil {
+			rs.cycle = err
+		}
+		return
+	}
+	rs.handleError(rs.currentBoundary(), err.Nodes[0], err)
+}
@@ -0,0 +1,154 @@
+package alien_test
+
+import (
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCycleDirectSelfRead(t *testing.T) {
+	var reported []*alien.CycleError
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		var cycleErr *alien.CycleError
+		require.ErrorAs(t, err, &cycleErr)
+		reported = append(reported, cycleErr)
+	})
+
+	a := alien.Signal(rs, 1)
+	var c *alien.ReadonlySignal[int]
+	c = alien.Computed(rs, func(oldValue int) int {
+		return a.Value() + c.Value()
+	}, alien.WithName("c"))
+
+	assert.Equal(t, 1, c.Value())
+	require.Len(t, reported, 1)
+	assert.Equal(t, []alien.SignalAware{c}, reported[0].Nodes)
+	assert.EqualError(t, reported[0], `alien: dependency cycle: computed "c" -> computed "c"`)
+
+	a.SetValue(2)
+	assert.Equal(t, 3, c.Value(), "uses the value from before the cycle")
+	assert.Len(t, reported, 2)
+}
+
+func TestCycleIndirectValueErr(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, "should be returned by ValueErr", err.Error())
+	})
+
+	a := alien.Signal(rs, 1)
+	var b, c *alien.ReadonlySignal[int]
+	b = alien.Computed(rs, func(oldValue int) int {
+		return a.Value() + c.Value()
+	}, alien.WithName("b"))
+	c = alien.Computed(rs, func(oldValue int) int {
+		return b.Value() * 2
+	}, alien.WithName("c"))
+
+	value, err := b.ValueErr()
+	assert.Equal(t, 1, value)
+	var cycleErr *alien.CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	assert.Equal(t, []alien.SignalAware{b, c}, cycleErr.Nodes)
+	assert.EqualError(t, err, `alien: dependency cycle: computed "b" -> computed "c" -> computed "b"`)
+}
+
+func TestCycleHandledInsideGetter(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+
+	a := alien.Signal(rs, 1)
+	var c *alien.ReadonlySignal[int]
+	handled := 0
+	c = alien.Computed(rs, func(oldValue int) int {
+		prev, err := c.ValueErr()
+		if err != nil {
+			handled++
+		}
+		return a.Value() + prev
+	})
+
+	assert.Equal(t, 1, c.Value())
+	a.SetValue(5)
+	assert.Equal(t, 6, c.Value())
+	assert.Equal(t, 2, handled)
+}
+
+func TestNoCycleValueErr(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	a := alien.Signal(rs, 1)
+	c := alien.Computed(rs, func(oldValue int) int {
+		return a.Value() * 2
+	})
+	value, err := c.ValueErr()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, value)
+}
+
+func TestCycleReachedFromEffect(t *testing.T) {
+	var reported []*alien.CycleError
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		var cycleErr *alien.CycleError
+		require.ErrorAs(t, err, &cycleErr)
+		reported = append(reported, cycleErr)
+	})
+
+	a := alien.Signal(rs, 1)
+	var c *alien.ReadonlySignal[int]
+	c = alien.Computed(rs, func(oldValue int) int {
+		return a.Value() + c.Value()
+	}, alien.WithName("c"))
+	var seen []int
+	alien.Effect(rs, func() error {
+		seen = append(seen, c.Value())
+		return nil
+	})
+	require.Len(t, reported, 1)
+
+	// The effect only finds out c changed by refreshing it, and the cycle
+	// shows up during that refresh rather than during the effect's read.
+	a.SetValue(2)
+	a.SetValue(3)
+	assert.Equal(t, []int{1, 3, 6}, seen)
+	require.Len(t, reported, 3)
+	for _, err := range reported {
+		assert.Equal(t, []alien.SignalAware{c}, err.Nodes)
+	}
+
+	// The stored cycle doesn't leak into unrelated reads.
+	value, err := c.ValueErr()
+	assert.NoError(t, err)
+	assert.Equal(t, 6, value)
+}
+
+func TestCycleReachedFromEffectGoesToBoundary(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+
+	a := alien.Signal(rs, 1)
+	var c *alien.ReadonlySignal[int]
+	c = alien.Computed(rs, func(oldValue int) int {
+		return a.Value() + c.Value()
+	})
+	handled := 0
+	alien.EffectScopeWithErrorHandler(rs, func() error {
+		alien.Effect(rs, func() error {
+			c.Value()
+			return nil
+		})
+		return nil
+	}, func(from alien.SignalAware, err error) alien.ErrorAction {
+		var cycleErr *alien.CycleError
+		assert.ErrorAs(t, err, &cycleErr)
+		handled++
+		return alien.RecoverError
+	})
+
+	a.SetValue(2)
+	assert.Equal(t, 2, handled)
+}
@@ -0,0 +1,15 @@
+//go:build !alien_debug
+
+package alien
+
+// debugState holds the bookkeeping of the graph invariant checker, which is
+// only compiled in with the alien_debug build tag; see debug_on.go.
+type debugState struct{}
+
+func (rs *ReactiveSystem) debugTrack(sub *signal)   {}
+func (rs *ReactiveSystem) debugUntrack(sub *signal) {}
+func (rs *ReactiveSystem) debugHold()               {}
+func (rs *ReactiveSystem) debugRelease()            {}
+func (rs *ReactiveSystem) debugCheck(node *signal)  {}
+
+func isInvariantViolation(r any) bool { return false }
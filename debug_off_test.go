@@ -0,0 +1,6 @@
+//go:build !alien_debug
+
+package alien_test
+
+// debugBuild reports whether the graph invariant checker is compiled in.
+const debugBuild = false
@@ -0,0 +1,219 @@
+//go:build alien_debug
+
+package alien
+
+import (
+	"fmt"
+	"strings"
+)
+
+// With the alien_debug build tag every node touched by linking, unlinking or
+// the end of tracking has its links checked on the spot:
+//
+//   - every link in deps belongs to the node and to its dep's subs, and
+//     depsTail is the last one (or, while tracking, somewhere in the chain);
+//   - every link in subs belongs to the node and to its sub's deps,
+//     prevSub mirrors nextSub, and subsTail is the last one;
+//   - fTracking is only set between startTracking and endTracking, and
+//     nothing is left tracking once no computation is running.
+//
+// A violation panics with an *InvariantError describing the node, which
+// error boundaries let through.
+
+// InvariantError is the panic value raised when the graph is found to be
+// inconsistent. It is only defined with the alien_debug build tag.
+type InvariantError struct {
+	Node SignalAware
+	Msg  string
+	Dump string
+}
+
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("alien: graph invariant violated at %s: %s\n%s", e.Node, e.Msg, e.Dump)
+}
+
+type debugState struct {
+	// tracking counts the open startTracking calls per node. They nest
+	// when an effect writes to its own deps and the flush reruns it while
+	// the outer run is still going.
+	tracking map[*signal]int
+	// held counts endTracking calls in progress. Unlinking cascades
+	// through nodes whose chains are only consistent again once the
+	// outermost one returns, so nodes touched meanwhile wait in pending.
+	held    int
+	pending []*signal
+}
+
+func isInvariantViolation(r any) bool {
+	_, ok := r.(*InvariantError)
+	return ok
+}
+
+// Parallel workers track and unlink on other goroutines, so nothing is
+// checked while rs.parallel is set.
+
+func (rs *ReactiveSystem) debugTrack(sub *signal) {
+	if rs.parallel {
+		return
+	}
+	if rs.debug.tracking == nil {
+		rs.debug.tracking = map[*signal]int{}
+	}
+	rs.debug.tracking[sub]++
+}
+
+func (rs *ReactiveSystem) debugUntrack(sub *signal) {
+	if rs.parallel {
+		return
+	}
+	if n := rs.debug.tracking[sub]; n > 1 {
+		rs.debug.tracking[sub] = n - 1
+	} else {
+		delete(rs.debug.tracking, sub)
+	}
+	rs.debugCheck(sub)
+}
+
+func (rs *ReactiveSystem) debugHold() {
+	rs.debug.held++
+}
+
+func (rs *ReactiveSystem) debugRelease() {
+	d := &rs.debug
+	if d.held--; d.held > 0 {
+		return
+	}
+	pending := d.pending
+	d.pending = nil
+	for _, node := range pending {
+		rs.debugCheck(node)
+	}
+}
+
+func (rs *ReactiveSystem) debugCheck(node *signal) {
+	if rs.parallel {
+		return
+	}
+	if rs.debug.held > 0 {
+		rs.debug.pending = append(rs.debug.pending, node)
+		return
+	}
+
+	seen := map[*link]bool{}
+	var last *link
+	tailInDeps := false
+	for l := node.deps; l != nil; l = l.nextDep {
+		switch {
+		case seen[l]:
+			debugFail(node, "deps chain loops back to link %p", l)
+		case l.dep == nil || l.sub == nil:
+			debugFail(node, "deps chain holds released link %p", l)
+		case l.sub != node:
+			debugFail(node, "deps chain holds link %p of %s", l, l.sub)
+		case !chainHas(l.dep.subs, l, func(l *link) *link { return l.nextSub }):
+			debugFail(node, "dep link %p is missing from the subs of %s", l, l.dep)
+		}
+		seen[l] = true
+		tailInDeps = tailInDeps || l == node.depsTail
+		last = l
+	}
+	if node.flags&fTracking != 0 {
+		if node.depsTail != nil && !tailInDeps {
+			debugFail(node, "depsTail %p is not in the deps chain", node.depsTail)
+		}
+	} else if node.depsTail != last {
+		debugFail(node, "depsTail is %p, last dep link is %p", node.depsTail, last)
+	}
+
+	clear(seen)
+	last = nil
+	for l := node.subs; l != nil; l = l.nextSub {
+		switch {
+		case seen[l]:
+			debugFail(node, "subs chain loops back to link %p", l)
+		case l.dep == nil || l.sub == nil:
+			debugFail(node, "subs chain holds released link %p", l)
+		case l.dep != node:
+			debugFail(node, "subs chain holds link %p of %s", l, l.dep)
+		case l.prevSub != last:
+			debugFail(node, "sub link %p has prevSub %p, previous link is %p", l, l.prevSub, last)
+		case !chainHas(l.sub.deps, l, func(l *link) *link { return l.nextDep }):
+			debugFail(node, "sub link %p is missing from the deps of %s", l, l.sub)
+		}
+		seen[l] = true
+		last = l
+	}
+	if node.subsTail != last {
+		debugFail(node, "subsTail is %p, last sub link is %p", node.subsTail, last)
+	}
+
+	if _, tracking := rs.debug.tracking[node]; node.flags&fTracking != 0 && !tracking {
+		debugFail(node, "fTracking is set outside startTracking and endTracking")
+	}
+	if rs.activeSub == nil && rs.activeScope == nil && len(rs.pauseStack) == 0 && len(rs.computing) == 0 {
+		for other := range rs.debug.tracking {
+			debugFail(other, "still tracking outside any computation")
+		}
+	}
+}
+
+// Reports whether l is reachable from first, giving up on loops.
+func chainHas(first, l *link, next func(*link) *link) bool {
+	seen := map[*link]bool{}
+	for cur := first; cur != nil && !seen[cur]; cur = next(cur) {
+		if cur == l {
+			return true
+		}
+		seen[cur] = true
+	}
+	return false
+}
+
+func debugFail(node *signal, format string, args ...any) {
+	panic(&InvariantError{
+		Node: node.public(),
+		Msg:  fmt.Sprintf(format, args...),
+		Dump: dumpNode(node),
+	})
+}
+
+// dumpLimit caps the links listed per chain, since a corrupt chain may loop.
+const dumpLimit = 32
+
+func dumpNode(node *signal) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "  node %p %s flags=%s\n", node, node, flagNames(node.flags))
+	fmt.Fprintf(&sb, "  deps (tail %p):\n", node.depsTail)
+	n := 0
+	for l := node.deps; l != nil && n < dumpLimit; l, n = l.nextDep, n+1 {
+		fmt.Fprintf(&sb, "    %p dep=%s sub=%s\n", l, describe(l.dep), describe(l.sub))
+	}
+	fmt.Fprintf(&sb, "  subs (tail %p):\n", node.subsTail)
+	n = 0
+	for l := node.subs; l != nil && n < dumpLimit; l, n = l.nextSub, n+1 {
+		fmt.Fprintf(&sb, "    %p sub=%s prev=%p next=%p\n", l, describe(l.sub), l.prevSub, l.nextSub)
+	}
+	return sb.String()
+}
+
+func describe(s *signal) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%p %s", s, s)
+}
+
+func flagNames(flags subscriberFlags) string {
+	names := []string{"Computed", "Effect", "Tracking", "Notified", "Recursed",
+		"Dirty", "PendingComputed", "PendingEffect", "EffectScope", "Parallel", "Ran"}
+	var set []string
+	for i, name := range names {
+		if flags&(1<<i) != 0 {
+			set = append(set, name)
+		}
+	}
+	if len(set) == 0 {
+		return "0"
+	}
+	return strings.Join(set, "|")
+}
@@ -0,0 +1,102 @@
+//go:build alien_debug
+
+package alien
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// debugGraph is a source read by two computeds, the second of which also
+// reads a second source, all kept linked by an effect.
+type debugGraph struct {
+	rs         *ReactiveSystem
+	src, other *WriteableSignal[int]
+	left       *ReadonlySignal[int]
+	right      *ReadonlySignal[int]
+}
+
+func newDebugGraph(t *testing.T) *debugGraph {
+	rs := CreateReactiveSystem(func(from SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	g := &debugGraph{rs: rs, src: Signal(rs, 1), other: Signal(rs, 2)}
+	g.left = Computed(rs, func(oldValue int) int { return g.src.Value() })
+	g.right = Computed(rs, func(oldValue int) int { return g.src.Value() + g.other.Value() })
+	Effect(rs, func() error {
+		g.left.Value()
+		g.right.Value()
+		return nil
+	})
+	return g
+}
+
+func checkPanics(t *testing.T, rs *ReactiveSystem, node *signal) *InvariantError {
+	t.Helper()
+	var invariantErr *InvariantError
+	func() {
+		defer func() {
+			err, ok := recover().(*InvariantError)
+			require.True(t, ok, "debugCheck panics with *InvariantError")
+			invariantErr = err
+		}()
+		rs.debugCheck(node)
+	}()
+	return invariantErr
+}
+
+func TestDebugCheckAcceptsConsistentGraph(t *testing.T) {
+	g := newDebugGraph(t)
+	for _, node := range []*signal{&g.src.signal, &g.other.signal, &g.left.signal, &g.right.signal} {
+		assert.NotPanics(t, func() { g.rs.debugCheck(node) })
+	}
+}
+
+func TestDebugCheckBrokenPrevSub(t *testing.T) {
+	g := newDebugGraph(t)
+	src := &g.src.signal
+	second := src.subs.nextSub
+	second.prevSub = nil
+
+	err := checkPanics(t, g.rs, src)
+	assert.Equal(t, fmt.Sprintf("sub link %p has prevSub 0x0, previous link is %p", second, src.subs), err.Msg)
+	assert.Equal(t, g.src, err.Node)
+	assert.Contains(t, err.Dump, fmt.Sprintf("%p sub=%s prev=0x0 next=0x0", second, describe(second.sub)))
+	assert.Contains(t, err.Error(), "alien: graph invariant violated at ")
+}
+
+func TestDebugCheckBrokenSubsTail(t *testing.T) {
+	g := newDebugGraph(t)
+	src := &g.src.signal
+	last := src.subsTail
+	src.subsTail = src.subs
+
+	err := checkPanics(t, g.rs, src)
+	assert.Equal(t, fmt.Sprintf("subsTail is %p, last sub link is %p", src.subs, last), err.Msg)
+	assert.Contains(t, err.Dump, fmt.Sprintf("subs (tail %p):", src.subs))
+}
+
+func TestDebugCheckBrokenDepsTail(t *testing.T) {
+	g := newDebugGraph(t)
+	right := &g.right.signal
+	last := right.depsTail
+	right.depsTail = right.deps
+
+	err := checkPanics(t, g.rs, right)
+	assert.Equal(t, fmt.Sprintf("depsTail is %p, last dep link is %p", right.deps, last), err.Msg)
+	assert.Equal(t, g.right, err.Node)
+	assert.Contains(t, err.Dump, fmt.Sprintf("deps (tail %p):", right.deps))
+}
+
+func TestDebugCheckStrayTracking(t *testing.T) {
+	g := newDebugGraph(t)
+	left := &g.left.signal
+	left.flags |= fTracking
+
+	err := checkPanics(t, g.rs, left)
+	assert.Equal(t, "fTracking is set outside startTracking and endTracking", err.Msg)
+	assert.Contains(t, err.Dump, "flags=Computed|Tracking")
+}
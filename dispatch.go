@@ -0,0 +1,66 @@
+package alien
+
+import "sync"
+
+// dispatcher is the only part of a ReactiveSystem that may be touched from
+// other goroutines. Work queued here is run by whoever owns the system.
+type dispatcher struct {
+	mu    sync.Mutex
+	queue []func()
+	ready chan struct{}
+}
+
+func (d *dispatcher) readyChan() chan struct{} {
+	if d.ready == nil {
+		d.ready = make(chan struct{}, 1)
+	}
+	return d.ready
+}
+
+// Dispatch queues fn to run on the goroutine that owns rs, the next time it
+// calls Drain. It is safe to call from any goroutine and is how results of
+// background work should be applied to signals.
+func (rs *ReactiveSystem) Dispatch(fn func()) {
+	d := &rs.dispatcher
+	d.mu.Lock()
+	d.queue = append(d.queue, fn)
+	ready := d.readyChan()
+	d.mu.Unlock()
+
+	select {
+	case ready <- struct{}{}:
+	default:
+	}
+}
+
+// Dispatched returns a channel that receives a value whenever work has been
+// queued with Dispatch. Owners typically select on it and call Drain.
+func (rs *ReactiveSystem) Dispatched() <-chan struct{} {
+	d := &rs.dispatcher
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.readyChan()
+}
+
+// Drain runs all work queued with Dispatch, including work queued while
+// draining, inside a single batch. It returns how many functions ran and
+// must only be called from the goroutine that owns rs.
+func (rs *ReactiveSystem) Drain() int {
+	d := &rs.dispatcher
+	ran := 0
+	rs.StartBatch()
+	defer rs.EndBatch()
+	for {
+		d.mu.Lock()
+		queue := d.queue
+		d.queue = nil
+		d.mu.Unlock()
+		if len(queue) == 0 {
+			return ran
+		}
+		for _, fn := range queue {
+			fn()
+		}
+		ran += len(queue)
+	}
+}
@@ -1,11 +1,13 @@
 package alien_test
 
 import (
+	"context"
 	"log"
 	"testing"
 
 	alien "github.com/delaneyj/alien-signals-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // should clear subscriptions when untracked by all subscribers
@@ -267,3 +269,99 @@ func TestShouldNotTriggerAfterStop(t *testing.T) {
 	count.SetValue(3)
 	assert.Equal(t, 2, triggers)
 }
+
+func TestEffectCtxCancelledOnRerunAndStop(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	count := alien.Signal(rs, 0)
+
+	var ctxs []context.Context
+	stop := alien.EffectCtx(rs, func(ctx context.Context) error {
+		count.Value()
+		ctxs = append(ctxs, ctx)
+		return nil
+	})
+	require.Len(t, ctxs, 1)
+	assert.NoError(t, ctxs[0].Err())
+
+	count.SetValue(1)
+	require.Len(t, ctxs, 2)
+	assert.ErrorIs(t, ctxs[0].Err(), context.Canceled, "superseded by the re-run")
+	assert.NoError(t, ctxs[1].Err())
+
+	stop()
+	assert.ErrorIs(t, ctxs[1].Err(), context.Canceled)
+	count.SetValue(2)
+	assert.Len(t, ctxs, 2)
+}
+
+func TestEffectCtxOnlyTracksSynchronousReads(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	count := alien.Signal(rs, 0)
+	other := alien.Signal(rs, 0)
+
+	runs := 0
+	var later func() int
+	alien.EffectCtx(rs, func(ctx context.Context) error {
+		runs++
+		count.Value()
+		// Background work hands its result back through Dispatch, and reads
+		// other once it is drained, long after fn returned.
+		go func() {
+			rs.Dispatch(func() {
+				if ctx.Err() == nil {
+					other.Value()
+				}
+			})
+		}()
+		later = func() int {
+			return other.Value()
+		}
+		return nil
+	})
+	drainOnce(t, rs)
+	assert.Equal(t, 0, later())
+
+	other.SetValue(1)
+	assert.Equal(t, 1, runs, "reads made after fn returned are not tracked")
+
+	count.SetValue(1)
+	assert.Equal(t, 2, runs)
+	drainOnce(t, rs)
+	other.SetValue(2)
+	assert.Equal(t, 2, runs)
+}
+
+// An effect whose computed turned out unchanged while an inner effect ran
+// must still be notified by the next write. Found by FuzzGraph.
+func TestShouldRerunOuterEffectAfterUnchangedComputed(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	src := alien.Signal(rs, 0)
+	c := alien.Computed(rs, func(oldValue int) int {
+		return src.Value()
+	})
+
+	var seen []int
+	alien.Effect(rs, func() error {
+		seen = append(seen, c.Value())
+		alien.Effect(rs, func() error {
+			src.Value()
+			return nil
+		})
+		return nil
+	})
+
+	rs.Batch(func() {
+		src.SetValue(3)
+		src.SetValue(0)
+	})
+	assert.Equal(t, []int{0}, seen)
+
+	src.SetValue(3)
+	assert.Equal(t, []int{0, 3}, seen)
+}
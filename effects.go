@@ -1,8 +1,14 @@
 package alien
 
+import (
+	"context"
+	"time"
+)
+
 type ErrFn func() error
 
-func Effect(rs *ReactiveSystem, fn ErrFn) ErrFn {
+func Effect(rs *ReactiveSystem, fn ErrFn, opts ...Option) ErrFn {
+	rs.sweepIfDropped()
 	e := &EffectRunner{
 		fn: fn,
 		signal: signal{
@@ -11,32 +17,56 @@ func Effect(rs *ReactiveSystem, fn ErrFn) ErrFn {
 	}
 	signal := &e.signal
 	signal.ref = e
-
-	if rs.activeSub != nil {
-		rs.link(signal, rs.activeSub)
-	} else if rs.activeScope != nil {
-		rs.link(signal, rs.activeScope)
-	}
+	rs.initNode(signal, opts)
+	rs.adopt(e)
 	rs.runEffect(e, signal)
 
 	return func() error {
-		rs.startTracking(signal)
-		rs.endTracking(signal)
+		rs.stopEffect(e)
 		return nil
 	}
 }
 
+// EffectCtx is Effect with a context that is cancelled when the effect
+// re-runs or is stopped, so work it starts in the background is torn down
+// with it. Only reads made before fn returns are tracked.
+func EffectCtx(rs *ReactiveSystem, fn func(ctx context.Context) error, opts ...Option) ErrFn {
+	return Effect(rs, func() error {
+		ctx, cancel := context.WithCancel(context.Background())
+		rs.onCleanup(cancel)
+		return fn(ctx)
+	}, opts...)
+}
+
 func (rs *ReactiveSystem) runEffect(e *EffectRunner, signal *signal) {
+	if rs.causality {
+		rs.recordEffectCause(signal)
+	}
+	if e.owned != nil || e.cleanups != nil || e.provided != nil {
+		rs.cleanupEffect(e)
+	}
 	prevSub := rs.activeSub
 	rs.activeSub = signal
 	rs.startTracking(signal)
-	if err := e.fn(); err != nil {
-		if rs.onError != nil {
-			rs.onError(e, err)
-		}
+	var start time.Time
+	if rs.tracer != nil || rs.metrics != nil {
+		start = time.Now()
+	}
+	err := rs.invoke(e, e.fn)
+	if rs.metrics != nil {
+		rs.metrics.recordEffectRun(signal, time.Since(start))
+	}
+	if rs.tracer != nil {
+		rs.tracer.EffectRun(e, time.Since(start), err)
+	}
+	if err != nil {
+		rs.reportError(e, err)
 	}
 	rs.endTracking(signal)
 	rs.activeSub = prevSub
+	if rs.idleQueue != nil {
+		rs.drainIdle()
+	}
 }
 
 func (rs *ReactiveSystem) notifyEffect(signal *signal) bool {
@@ -49,16 +79,58 @@ func (rs *ReactiveSystem) notifyEffect(signal *signal) bool {
 		return false
 	}
 
-	if flags&fDirty != 0 ||
-		(flags&fPendingComputed != 0 && rs.updateDirtyFlag(signal, flags)) {
-		rs.runEffect(signal.ref.(*EffectRunner), signal)
+	if flags&(fDirty|fPendingComputed) != 0 && rs.updateEffectDirtyFlag(signal, flags) {
+		if rs.allowFlushIteration(signal) {
+			rs.runEffect(signal.ref.(*EffectRunner), signal)
+		} else {
+			// Forget the pending run so the next write can queue it again.
+			signal.flags &^= fPropagated
+		}
 	} else {
-		rs.processPendingInnerEffects(signal, flags)
+		// updateDirtyFlag may have cleared PendingComputed; passing the old
+		// flags would set it again and hide the effect from later writes.
+		rs.processPendingInnerEffects(signal, signal.flags)
 	}
 	return true
 }
 
-func EffectScope(rs *ReactiveSystem, scopedFn ErrFn) (stopScope ErrFn) {
+// updateDirtyFlag for an effect, which first refreshes its dependencies in
+// parallel if it asked for that. The computeds it refreshes are evaluated on
+// the effect's behalf, so a cycle they run into, or a panic they raise, is
+// reported like one the effect's own reads would have run into. An effect
+// whose check panicked is left as it was before the write.
+func (rs *ReactiveSystem) updateEffectDirtyFlag(signal *signal, flags subscriberFlags) bool {
+	parallel := flags&fParallel != 0 && rs.workers > 1
+	if flags&fDirty != 0 && !parallel {
+		return true
+	}
+	e := signal.ref.(*EffectRunner)
+	outer := rs.cycle
+	rs.cycle = nil
+	dirty := false
+	err := rs.invoke(e, func() error {
+		if parallel {
+			rs.refreshParallel(signal)
+			flags = signal.flags
+		}
+		dirty = flags&fDirty != 0 ||
+			(flags&fPendingComputed != 0 && rs.updateDirtyFlag(signal, flags))
+		return nil
+	})
+	cycle := rs.cycle
+	rs.cycle = outer
+	if err != nil {
+		signal.flags &^= fPropagated
+		rs.reportError(e, err)
+		return false
+	}
+	if cycle != nil {
+		rs.handleError(e.boundary, cycle.Nodes[0], cycle)
+	}
+	return dirty
+}
+
+func EffectScope(rs *ReactiveSystem, scopedFn ErrFn, opts ...Option) (stopScope ErrFn) {
 	e := &EffectRunner{
 		signal: signal{
 			flags: fEffect | fEffectScope,
@@ -66,10 +138,13 @@ func EffectScope(rs *ReactiveSystem, scopedFn ErrFn) (stopScope ErrFn) {
 	}
 	signal := &e.signal
 	signal.ref = e
-	rs.runEffectScope(e, signal, scopedFn)
+	rs.initNode(signal, opts)
+	rs.adopt(e)
+	if err := rs.runEffectScope(e, signal, scopedFn); err != nil {
+		rs.reportError(e, err)
+	}
 	return func() error {
-		rs.startTracking(signal)
-		rs.endTracking(signal)
+		rs.stopEffect(e)
 		return nil
 	}
 }
@@ -77,23 +152,43 @@ func EffectScope(rs *ReactiveSystem, scopedFn ErrFn) (stopScope ErrFn) {
 type EffectRunner struct {
 	signal
 	fn ErrFn
+
+	// parent is the effect or scope that was running when this one was
+	// created. Roots keep it for lookups but are not linked to it.
+	parent *EffectRunner
+	// boundary is the closest ancestor with an error handler.
+	boundary *EffectRunner
+	onErr    ScopeErrorHandler
+	// owned holds the computeds created while this runner was the owner;
+	// they are disposed along with it.
+	owned    []*signal
+	cleanups []func()
+	provided map[any]any
 }
 
 func (e *EffectRunner) isSignalAware() {}
 
-func (rs *ReactiveSystem) runEffectScope(e *EffectRunner, signal *signal, scopedFn ErrFn) {
-	prevSub := rs.activeSub
+func (rs *ReactiveSystem) runEffectScope(e *EffectRunner, signal *signal, scopedFn ErrFn) error {
+	prevSub, prevScope := rs.activeSub, rs.activeScope
+	rs.activeSub = nil
 	rs.activeScope = signal
 	rs.startTracking(signal)
 
-	if err := scopedFn(); err != nil {
-		if rs.onError != nil {
-			rs.onError(e, err)
-		}
+	var start time.Time
+	if rs.tracer != nil {
+		start = time.Now()
+	}
+	err := rs.invoke(e, scopedFn)
+	if rs.tracer != nil {
+		rs.tracer.EffectRun(e, time.Since(start), err)
 	}
 
-	rs.activeScope = prevSub
+	rs.activeSub, rs.activeScope = prevSub, prevScope
 	rs.endTracking(signal)
+	if rs.idleQueue != nil {
+		rs.drainIdle()
+	}
+	return err
 }
 
 // Ensures all pending internal effects for the given subscriber are processed.
@@ -108,17 +203,16 @@ func (rs *ReactiveSystem) runEffectScope(e *EffectRunner, signal *signal, scoped
 func (rs *ReactiveSystem) processPendingInnerEffects(sub *signal, flags subscriberFlags) {
 	if flags&fPendingEffect != 0 {
 		sub.flags = flags & ^fPendingEffect
-		link := sub.deps
-		for {
+		for link := sub.deps; link != nil; link = link.nextDep {
 			dep := link.dep
 			flags = dep.flags
 			if flags&fEffect != 0 && flags&fPropagated != 0 {
 				rs.notifyEffect(dep)
-			}
-			link = link.nextDep
-
-			if link == nil {
-				break
+				// The effect may have stopped sub, releasing this link and
+				// disposing the rest of the chain with it.
+				if link.sub != sub {
+					break
+				}
 			}
 		}
 	}
@@ -129,12 +223,28 @@ func (rs *ReactiveSystem) processPendingInnerEffects(sub *signal, flags subscrib
 // Iterates through all queued effects, calling notifyEffect on each.
 // If an effect remains partially handled, its flags are updated, and future
 // notifications may be triggered until fully handled.
+//
+// Effects that run as a result of writes made by other effects flush
+// recursively; all of those nested flushes share one count of each effect's
+// runs, and once an effect runs too often the remaining queue is dropped.
 func (rs *ReactiveSystem) processEffectNotifications() {
-	for rs.queuedEffects != nil {
-		effect := rs.queuedEffects.target
-		rs.queuedEffects = rs.queuedEffects.linked
-		if rs.queuedEffects == nil {
-			rs.queuedEffectsTail = nil
+	rs.flush.depth++
+	defer func() {
+		if rs.flush.depth--; rs.flush.depth == 0 {
+			rs.finishFlush()
+		}
+	}()
+
+	for rs.queuedHead < len(rs.queuedEffects) {
+		effect := rs.queuedEffects[rs.queuedHead]
+		rs.queuedEffects[rs.queuedHead] = nil
+		if rs.queuedHead++; rs.queuedHead == len(rs.queuedEffects) {
+			rs.queuedEffects = rs.queuedEffects[:0]
+			rs.queuedHead = 0
+		}
+		if rs.flush.aborted {
+			effect.flags &^= fNotified | fPropagated
+			continue
 		}
 		if !rs.notifyEffect(effect) {
 			effect.flags = effect.flags & ^fNotified
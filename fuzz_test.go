@@ -0,0 +1,306 @@
+package alien_test
+
+import (
+	"fmt"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+)
+
+// fuzzBytes hands out the fuzzer's input one byte at a time, and zeroes once
+// it runs out, so every input describes some program.
+type fuzzBytes struct {
+	data []byte
+}
+
+func (b *fuzzBytes) next(n int) int {
+	if len(b.data) == 0 {
+		return 0
+	}
+	v := b.data[0]
+	b.data = b.data[1:]
+	return int(v) % n
+}
+
+func (b *fuzzBytes) done() bool {
+	return len(b.data) == 0
+}
+
+// fuzzNode is a signal or computed of a generated graph. Computeds read
+// deps[0], then deps[1] if that is even and deps[2] otherwise, or all of
+// them when cond is false. Values stay small so that equal results, and
+// with them skipped updates, are common.
+type fuzzNode struct {
+	signal *alien.WriteableSignal[int]
+	deps   []int
+	cond   bool
+	read   alien.Readable[int]
+	evals  int
+}
+
+func (n *fuzzNode) compute(value func(dep int) int) int {
+	if n.cond {
+		if value(n.deps[0])%2 == 0 {
+			return (value(n.deps[0]) + value(n.deps[1])) % 5
+		}
+		return (value(n.deps[0]) + value(n.deps[2])) % 5
+	}
+	sum := 0
+	for _, dep := range n.deps {
+		sum += value(dep)
+	}
+	return sum % 5
+}
+
+// fuzzEffect reads some nodes. An effect with a child creates a new child
+// effect, a new instance, every time it runs.
+type fuzzEffect struct {
+	reads []int
+	child *fuzzEffect
+	scope int
+}
+
+type fuzzInstance struct {
+	effect  *fuzzEffect
+	seen    map[int]int
+	runs    int
+	stopped bool
+	child   *fuzzInstance
+	parent  *fuzzInstance
+	id      int
+	stop    alien.ErrFn
+}
+
+type fuzzGraph struct {
+	t         *testing.T
+	rs        *alien.ReactiveSystem
+	nodes     []*fuzzNode
+	instances []*fuzzInstance
+	roots     []*fuzzInstance
+	scopes    []alien.ErrFn
+	scopeOf   map[*fuzzInstance]int
+	// log lists effect runs in the order they happened.
+	log []*fuzzInstance
+}
+
+// model re-evaluates every node from the current signal values.
+func (g *fuzzGraph) model() []int {
+	values := make([]int, len(g.nodes))
+	for i, n := range g.nodes {
+		if n.signal != nil {
+			values[i] = n.signal.Value()
+			continue
+		}
+		values[i] = n.compute(func(dep int) int { return values[dep] })
+	}
+	return values
+}
+
+func (g *fuzzGraph) run(e *fuzzEffect, parent *fuzzInstance) *fuzzInstance {
+	inst := &fuzzInstance{effect: e, parent: parent, id: len(g.instances)}
+	g.instances = append(g.instances, inst)
+	inst.stop = alien.Effect(g.rs, func() error {
+		if inst.stopped {
+			g.t.Fatalf("stopped effect %d ran", inst.id)
+		}
+		inst.runs++
+		g.log = append(g.log, inst)
+		inst.seen = map[int]int{}
+		for _, node := range e.reads {
+			inst.seen[node] = g.nodes[node].read.Value()
+		}
+		if e.child != nil {
+			if inst.child != nil {
+				g.markStopped(inst.child)
+			}
+			inst.child = g.run(e.child, inst)
+		}
+		return nil
+	})
+	return inst
+}
+
+func (g *fuzzGraph) markStopped(inst *fuzzInstance) {
+	inst.stopped = true
+	if inst.child != nil {
+		g.markStopped(inst.child)
+	}
+}
+
+func buildFuzzGraph(t *testing.T, in *fuzzBytes) *fuzzGraph {
+	g := &fuzzGraph{
+		t: t,
+		rs: alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+			t.Fatal(err)
+		}),
+		scopeOf: map[*fuzzInstance]int{},
+	}
+
+	for range 1 + in.next(4) {
+		s := alien.Signal(g.rs, in.next(5))
+		g.nodes = append(g.nodes, &fuzzNode{signal: s, read: s})
+	}
+	for range in.next(10) {
+		n := &fuzzNode{cond: in.next(2) == 1}
+		count := 1 + in.next(3)
+		if n.cond {
+			count = 3
+		}
+		for range count {
+			n.deps = append(n.deps, in.next(len(g.nodes)))
+		}
+		n.read = alien.Computed(g.rs, func(oldValue int) int {
+			n.evals++
+			return n.compute(func(dep int) int { return g.nodes[dep].read.Value() })
+		})
+		g.nodes = append(g.nodes, n)
+	}
+
+	g.scopes = make([]alien.ErrFn, 1+in.next(2))
+	var effects []*fuzzEffect
+	for range 1 + in.next(5) {
+		e := &fuzzEffect{scope: in.next(len(g.scopes) + 1)}
+		for range 1 + in.next(3) {
+			e.reads = append(e.reads, in.next(len(g.nodes)))
+		}
+		if in.next(3) == 0 {
+			e.child = &fuzzEffect{}
+			for range 1 + in.next(2) {
+				e.child.reads = append(e.child.reads, in.next(len(g.nodes)))
+			}
+		}
+		effects = append(effects, e)
+	}
+
+	// Scope 0 stands for effects created at the top level.
+	for _, e := range effects {
+		if e.scope == 0 {
+			inst := g.run(e, nil)
+			g.roots = append(g.roots, inst)
+		}
+	}
+	for i := range g.scopes {
+		g.scopes[i] = alien.EffectScope(g.rs, func() error {
+			for _, e := range effects {
+				if e.scope == i+1 {
+					inst := g.run(e, nil)
+					g.roots = append(g.roots, inst)
+					g.scopeOf[inst] = i
+				}
+			}
+			return nil
+		})
+	}
+	return g
+}
+
+// check compares everything observed during one operation with the model.
+func (g *fuzzGraph) check(op string, evalsBefore []int, runsBefore map[*fuzzInstance]int) {
+	t := g.t
+	for i, n := range g.nodes {
+		if n.signal == nil && n.evals-evalsBefore[i] > 1 {
+			t.Fatalf("%s: computed %d evaluated %d times", op, i, n.evals-evalsBefore[i])
+		}
+	}
+	// An outer effect runs, and replaces its children, before any of them.
+	ran := map[*fuzzInstance]int{}
+	for i, inst := range g.log {
+		ran[inst] = i
+		if inst.parent == nil {
+			continue
+		}
+		if _, ok := ran[inst.parent]; !ok && inst.parent.runs > runsBefore[inst.parent] {
+			t.Fatalf("%s: effect %d ran before its parent %d", op, inst.id, inst.parent.id)
+		}
+	}
+	g.log = g.log[:0]
+
+	values := g.model()
+	for _, inst := range g.instances {
+		if inst.stopped {
+			continue
+		}
+		if runs := inst.runs - runsBefore[inst]; runs > 1 {
+			t.Fatalf("%s: effect ran %d times", op, runs)
+		}
+		for node, seen := range inst.seen {
+			if seen != values[node] {
+				t.Fatalf("%s: effect saw node %d = %d, model says %d", op, node, seen, values[node])
+			}
+		}
+	}
+}
+
+func (g *fuzzGraph) step(in *fuzzBytes) string {
+	signals := 0
+	for _, n := range g.nodes {
+		if n.signal != nil {
+			signals++
+		}
+	}
+	write := func() string {
+		i, v := in.next(signals), in.next(5)
+		g.nodes[i].signal.SetValue(v)
+		return fmt.Sprintf("set %d=%d", i, v)
+	}
+
+	switch in.next(5) {
+	case 0, 1:
+		return write()
+	case 2:
+		var ops []string
+		g.rs.Batch(func() {
+			for range 2 + in.next(3) {
+				ops = append(ops, write())
+			}
+		})
+		return fmt.Sprint("batch ", ops)
+	case 3:
+		i := in.next(len(g.roots))
+		inst := g.roots[i]
+		inst.stop()
+		g.markStopped(inst)
+		return fmt.Sprintf("stop effect %d", i)
+	default:
+		if in.next(2) == 0 {
+			i := in.next(len(g.scopes))
+			g.scopes[i]()
+			for inst, scope := range g.scopeOf {
+				if scope == i {
+					g.markStopped(inst)
+				}
+			}
+			return fmt.Sprintf("stop scope %d", i)
+		}
+		i := in.next(len(g.nodes))
+		if got, want := g.nodes[i].read.Value(), g.model()[i]; got != want {
+			g.t.Fatalf("read node %d = %d, model says %d", i, got, want)
+		}
+		return fmt.Sprintf("read %d", i)
+	}
+}
+
+func FuzzGraph(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{2, 1, 2, 5, 0, 0, 1, 1, 1, 0, 2, 3, 1, 2, 0, 0, 1, 0, 1, 2, 1, 3, 0, 0, 4, 2, 1, 1, 3})
+	f.Add([]byte{3, 0, 1, 2, 3, 8, 1, 0, 1, 2, 0, 2, 3, 4, 1, 4, 5, 6, 0, 1, 6, 7, 1, 3, 2, 8, 9, 4, 1, 2, 0, 0, 3, 1, 10, 0, 2, 1, 1, 2, 3, 4, 2, 4, 0, 1, 2, 0, 1, 4, 2, 1, 1, 3, 0, 3, 4, 4, 5})
+	f.Add([]byte{1, 2, 9, 1, 0, 0, 0, 0, 1, 1, 2, 1, 2, 3, 0, 3, 1, 4, 5, 0, 5, 1, 6, 6, 2, 7, 8, 1, 8, 5, 1, 9, 0, 1, 1, 0, 9, 1, 10, 4, 0, 2, 3, 0, 1, 0, 2, 0, 3, 0, 4, 0, 1, 0, 2})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		in := &fuzzBytes{data: data}
+		g := buildFuzzGraph(t, in)
+		g.check("setup", make([]int, len(g.nodes)), map[*fuzzInstance]int{})
+
+		for !in.done() {
+			evals := make([]int, len(g.nodes))
+			for i, n := range g.nodes {
+				evals[i] = n.evals
+			}
+			runs := map[*fuzzInstance]int{}
+			for _, inst := range g.instances {
+				runs[inst] = inst.runs
+			}
+			op := g.step(in)
+			g.check(op, evals, runs)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package alien
+
+// MapKeyed maps every item of list with fn, keeping the result for an item
+// for as long as an item with the same key stays in the list. fn runs in a
+// scope of its own that is stopped when the key disappears, so anything it
+// creates lives exactly as long as the item. item and index follow the
+// latest value and position of the key without calling fn again.
+//
+// A list update costs one call to fn per inserted key plus one scope stop
+// per removed key. Keys that appear more than once are matched up in order.
+// Calling stop, or stopping the owner, stops following list and every item
+// scope.
+func MapKeyed[T comparable, K comparable, U any](rs *ReactiveSystem, list Readable[[]T], key func(T) K, fn func(item Readable[T], index Readable[int]) U, opts ...Option) (mapped Readable[[]U], stop ErrFn) {
+	type entry struct {
+		key     K
+		item    *WriteableSignal[T]
+		index   *WriteableSignal[int]
+		value   U
+		dispose func()
+	}
+	var entries []*entry
+	return mapList(rs, list, func(items []T) []U {
+		byKey := make(map[K][]*entry, len(entries))
+		for _, e := range entries {
+			byKey[e.key] = append(byKey[e.key], e)
+		}
+
+		next := make([]*entry, len(items))
+		var added []int
+		for i, v := range items {
+			k := key(v)
+			if reuse := byKey[k]; len(reuse) > 0 {
+				e := reuse[0]
+				byKey[k] = reuse[1:]
+				e.item.SetValue(v)
+				e.index.SetValue(i)
+				next[i] = e
+			} else {
+				next[i] = &entry{key: k}
+				added = append(added, i)
+			}
+		}
+		for _, reuse := range byKey {
+			for _, e := range reuse {
+				e.dispose()
+			}
+		}
+		for _, i := range added {
+			e := next[i]
+			e.item = Signal(rs, items[i])
+			e.index = Signal(rs, i)
+			e.value, e.dispose = mapItem(rs, func() U {
+				return fn(e.item, e.index)
+			})
+		}
+
+		entries = next
+		values := make([]U, len(entries))
+		for i, e := range entries {
+			values[i] = e.value
+		}
+		return values
+	}, func() {
+		for _, e := range entries {
+			e.dispose()
+		}
+		entries = nil
+	}, opts)
+}
+
+// MapIndexed maps every position of list with fn. The result for a position
+// is kept for as long as the list is at least that long; item follows the
+// value at that position. Like MapKeyed, fn runs in a scope of its own that
+// is stopped when the list shrinks below its position, and the whole
+// mapping is stopped by stop or with the owner.
+func MapIndexed[T comparable, U any](rs *ReactiveSystem, list Readable[[]T], fn func(item Readable[T], index int) U, opts ...Option) (mapped Readable[[]U], stop ErrFn) {
+	type entry struct {
+		item    *WriteableSignal[T]
+		value   U
+		dispose func()
+	}
+	var entries []*entry
+	return mapList(rs, list, func(items []T) []U {
+		for i := len(items); i < len(entries); i++ {
+			entries[i].dispose()
+		}
+		kept := min(len(items), len(entries))
+		for i := range kept {
+			entries[i].item.SetValue(items[i])
+		}
+		entries = entries[:kept]
+		for i := kept; i < len(items); i++ {
+			e := &entry{item: Signal(rs, items[i])}
+			e.value, e.dispose = mapItem(rs, func() U {
+				return fn(e.item, i)
+			})
+			entries = append(entries, e)
+		}
+
+		values := make([]U, len(entries))
+		for i, e := range entries {
+			values[i] = e.value
+		}
+		return values
+	}, func() {
+		for _, e := range entries {
+			e.dispose()
+		}
+		entries = nil
+	}, opts)
+}
+
+// Shared plumbing of MapKeyed and MapIndexed. update reconciles the items
+// with the previous run and returns the mapped values; disposeAll stops
+// every item scope once the list is stopped.
+func mapList[T, U any](rs *ReactiveSystem, list Readable[[]T], update func(items []T) []U, disposeAll func(), opts []Option) (Readable[[]U], ErrFn) {
+	version := Signal(rs, uint64(0))
+	var values []U
+
+	// Item scopes outlive individual runs of the effect, so they are stopped
+	// by a scope that only goes away with its owner or by stop.
+	stop := EffectScope(rs, func() error {
+		rs.onCleanup(disposeAll)
+		Effect(rs, func() error {
+			items := list.Value()
+			rs.Batch(func() {
+				values = update(items)
+				version.SetValue(version.value + 1)
+			})
+			return nil
+		}, opts...)
+		return nil
+	})
+	return ReadableFunc[[]U](func() []U {
+		version.Value()
+		return values
+	}), stop
+}
+
+// Runs fn in a detached scope and returns its result together with the
+// function that stops the scope.
+func mapItem[U any](rs *ReactiveSystem, fn func() U) (U, func()) {
+	var (
+		value   U
+		dispose func()
+	)
+	Root(rs, func(d func()) error {
+		value, dispose = fn(), d
+		return nil
+	})
+	return value, dispose
+}
@@ -0,0 +1,181 @@
+package alien_test
+
+import (
+	"fmt"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type todo struct {
+	ID   int
+	Text string
+}
+
+func TestMapKeyedReusesItems(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	todos := alien.Signal(rs, &[]todo{{1, "write"}, {2, "test"}, {3, "ship"}})
+	tick := alien.Signal(rs, 0)
+
+	var created []int
+	alive := map[int]int{}
+	rows, _ := alien.MapKeyed(rs, alien.ReadableFunc[[]todo](func() []todo {
+		return *todos.Value()
+	}), func(item todo) int {
+		return item.ID
+	}, func(item alien.Readable[todo], index alien.Readable[int]) alien.Readable[string] {
+		id := item.Value().ID
+		created = append(created, id)
+		// Runs for as long as the row's scope lives.
+		alien.Effect(rs, func() error {
+			tick.Value()
+			alive[id]++
+			return nil
+		})
+		return alien.ReadableFunc[string](func() string {
+			return fmt.Sprintf("%d. %s", index.Value()+1, item.Value().Text)
+		})
+	})
+
+	render := func() []string {
+		var out []string
+		for _, row := range rows.Value() {
+			out = append(out, row.Value())
+		}
+		return out
+	}
+	assert.Equal(t, []string{"1. write", "2. test", "3. ship"}, render())
+
+	// Reorder, edit one item, drop one and insert one.
+	todos.SetValue(&[]todo{{3, "ship it"}, {4, "celebrate"}, {1, "write"}})
+	assert.Equal(t, []string{"1. ship it", "2. celebrate", "3. write"}, render())
+	assert.Equal(t, []int{1, 2, 3, 4}, created, "only the inserted item is mapped")
+
+	tick.SetValue(1)
+	assert.Equal(t, map[int]int{1: 2, 2: 1, 3: 2, 4: 2}, alive, "the removed item's scope is stopped")
+}
+
+func TestMapKeyedDuplicateKeys(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	words := alien.Signal(rs, &[]string{"a", "b", "a"})
+
+	calls := 0
+	upper, _ := alien.MapKeyed(rs, alien.ReadableFunc[[]string](func() []string {
+		return *words.Value()
+	}), func(s string) string {
+		return s
+	}, func(item alien.Readable[string], index alien.Readable[int]) string {
+		calls++
+		return item.Value() + item.Value()
+	})
+	assert.Equal(t, []string{"aa", "bb", "aa"}, upper.Value())
+
+	words.SetValue(&[]string{"a", "a", "a"})
+	assert.Equal(t, []string{"aa", "aa", "aa"}, upper.Value())
+	assert.Equal(t, 4, calls)
+}
+
+func TestMapIndexed(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	scores := alien.Signal(rs, &[]int{10, 20})
+	tick := alien.Signal(rs, 0)
+
+	var created []int
+	alive := map[int]int{}
+	labels, _ := alien.MapIndexed(rs, alien.ReadableFunc[[]int](func() []int {
+		return *scores.Value()
+	}), func(item alien.Readable[int], index int) alien.Readable[string] {
+		created = append(created, index)
+		alien.Effect(rs, func() error {
+			tick.Value()
+			alive[index]++
+			return nil
+		})
+		return alien.ReadableFunc[string](func() string {
+			return fmt.Sprintf("#%d: %d", index+1, item.Value())
+		})
+	})
+	render := func() []string {
+		var out []string
+		for _, label := range labels.Value() {
+			out = append(out, label.Value())
+		}
+		return out
+	}
+	assert.Equal(t, []string{"#1: 10", "#2: 20"}, render())
+
+	scores.SetValue(&[]int{15, 20, 30})
+	assert.Equal(t, []string{"#1: 15", "#2: 20", "#3: 30"}, render())
+	scores.SetValue(&[]int{5})
+	assert.Equal(t, []string{"#1: 5"}, render())
+	assert.Equal(t, []int{0, 1, 2}, created)
+
+	tick.SetValue(1)
+	assert.Equal(t, map[int]int{0: 2, 1: 1, 2: 1}, alive)
+}
+
+func TestMapKeyedStoppedWithOwner(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	ids := alien.Signal(rs, &[]int{1, 2})
+	tick := alien.Signal(rs, 0)
+
+	runs := 0
+	stop := alien.EffectScope(rs, func() error {
+		alien.MapKeyed(rs, alien.ReadableFunc[[]int](func() []int {
+			return *ids.Value()
+		}), func(id int) int {
+			return id
+		}, func(item alien.Readable[int], index alien.Readable[int]) struct{} {
+			alien.Effect(rs, func() error {
+				tick.Value()
+				runs++
+				return nil
+			})
+			return struct{}{}
+		})
+		return nil
+	})
+	assert.Equal(t, 2, runs)
+
+	stop()
+	tick.SetValue(1)
+	ids.SetValue(&[]int{1, 2, 3})
+	assert.Equal(t, 2, runs)
+}
+
+func TestMapIndexedStop(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	scores := alien.Signal(rs, &[]int{10, 20})
+	tick := alien.Signal(rs, 0)
+
+	created, runs := 0, 0
+	labels, stop := alien.MapIndexed(rs, alien.ReadableFunc[[]int](func() []int {
+		return *scores.Value()
+	}), func(item alien.Readable[int], index int) int {
+		created++
+		alien.Effect(rs, func() error {
+			tick.Value()
+			runs++
+			return nil
+		})
+		return index
+	})
+	assert.NoError(t, stop())
+
+	tick.SetValue(1)
+	scores.SetValue(&[]int{10, 20, 30})
+	assert.Equal(t, 2, created)
+	assert.Equal(t, 2, runs)
+	assert.Equal(t, []int{0, 1}, labels.Value())
+}
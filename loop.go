@@ -0,0 +1,114 @@
+package alien
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxFlushIterations is the number of times a single effect may run
+// in one flush before the flush is considered stuck in a loop.
+const DefaultMaxFlushIterations = 100_000
+
+// WithMaxFlushIterations overrides DefaultMaxFlushIterations. Effects that
+// run as a consequence of other effects' writes belong to the flush that
+// started it all, so an effect that keeps writing its own dependencies is
+// cut off instead of hanging the process. Only repeated runs of the same
+// effect count towards the limit; a flush may run any number of distinct
+// effects once each. It panics if n is not positive, as no flush could run
+// at all.
+func WithMaxFlushIterations(n int) SystemOption {
+	if n <= 0 {
+		panic(fmt.Sprintf("alien: WithMaxFlushIterations(%d) needs a positive limit", n))
+	}
+	return func(rs *ReactiveSystem) {
+		rs.maxFlushIterations = n
+	}
+}
+
+// LoopError is reported to OnErrorFunc when a flush is aborted because it
+// exceeded its iteration limit.
+type LoopError struct {
+	// Iterations is the limit an effect ran past: how many times it had
+	// run in the flush when it was cut off.
+	Iterations int
+	// Nodes are the effects that kept re-running, in the order they were
+	// first seen repeating.
+	Nodes []SignalAware
+}
+
+func (e *LoopError) Error() string {
+	names := make([]string, len(e.Nodes))
+	for i, node := range e.Nodes {
+		names[i] = node.String()
+	}
+	return fmt.Sprintf(
+		"alien: effect flush aborted after %d iterations, cycling through %s",
+		e.Iterations, strings.Join(names, ", "),
+	)
+}
+
+type flushState struct {
+	depth   int
+	aborted bool
+	// Effects that have run in this flush, marked fRan so that a second run
+	// can be told apart from the first without a lookup.
+	ran []*signal
+	// Run counts of the effects that ran more than once, in the order they
+	// started repeating.
+	runs  map[*signal]int
+	order []*signal
+}
+
+// Accounts for one effect run in the current flush. Returns false once the
+// flush has been aborted, in which case the effect must not run.
+func (rs *ReactiveSystem) allowFlushIteration(effect *signal) bool {
+	f := &rs.flush
+	if f.aborted {
+		return false
+	}
+	if effect.flags&fRan == 0 {
+		effect.flags |= fRan
+		f.ran = append(f.ran, effect)
+		return true
+	}
+	if f.runs == nil {
+		f.runs = map[*signal]int{}
+	}
+	runs := f.runs[effect]
+	if runs == 0 {
+		runs = 1
+		f.order = append(f.order, effect)
+	}
+	runs++
+	f.runs[effect] = runs
+	if runs > rs.maxFlushIterations {
+		f.aborted = true
+		return false
+	}
+	return true
+}
+
+func (rs *ReactiveSystem) finishFlush() {
+	f := rs.flush
+	for i, effect := range f.ran {
+		effect.flags &^= fRan
+		f.ran[i] = nil
+	}
+	rs.flush = flushState{ran: f.ran[:0]}
+	if rs.idleQueue != nil {
+		rs.drainIdle()
+	}
+	if !f.aborted || rs.onError == nil {
+		return
+	}
+
+	// Effects that repeated a few times early on and then settled are not
+	// part of the loop; name those still going past half the limit.
+	err := &LoopError{Iterations: rs.maxFlushIterations}
+	for _, effect := range f.order {
+		if f.runs[effect] > rs.maxFlushIterations/2 {
+			err.Nodes = append(err.Nodes, effect.public())
+		}
+	}
+	rs.onError(err.Nodes[0], err)
+}
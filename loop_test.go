@@ -0,0 +1,148 @@
+package alien_test
+
+import (
+	"errors"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoopDetectionDirectWriteBack(t *testing.T) {
+	var loopErrs []*alien.LoopError
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		var loopErr *alien.LoopError
+		require.True(t, errors.As(err, &loopErr), err.Error())
+		loopErrs = append(loopErrs, loopErr)
+	}, alien.WithMaxFlushIterations(100))
+
+	a := alien.Signal(rs, 0)
+	runs := 0
+	alien.EffectScope(rs, func() error {
+		alien.Effect(rs, func() error {
+			runs++
+			a.SetValue(a.Value() + 1)
+			return nil
+		}, alien.WithName("increment"))
+		return nil
+	})
+	assert.Equal(t, 1, runs)
+
+	a.SetValue(100)
+	assert.Equal(t, 101, runs)
+	require.Len(t, loopErrs, 1)
+	assert.Equal(t, 100, loopErrs[0].Iterations)
+	require.Len(t, loopErrs[0].Nodes, 1)
+	assert.Equal(t, "increment", loopErrs[0].Nodes[0].Name())
+	assert.EqualError(t, loopErrs[0],
+		`alien: effect flush aborted after 100 iterations, cycling through effect "increment"`)
+
+	// The system recovers: the next write starts a fresh flush.
+	a.SetValue(0)
+	assert.Equal(t, 201, runs)
+	assert.Len(t, loopErrs, 2)
+}
+
+func TestLoopDetectionIndirectWriteBack(t *testing.T) {
+	var loopErr *alien.LoopError
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		require.ErrorAs(t, err, &loopErr)
+	}, alien.WithMaxFlushIterations(50))
+
+	a := alien.Signal(rs, 0)
+	b := alien.Signal(rs, 0)
+	unrelatedRuns := 0
+	alien.EffectScope(rs, func() error {
+		alien.Effect(rs, func() error {
+			b.SetValue(a.Value() + 1)
+			return nil
+		}, alien.WithName("a->b"))
+		alien.Effect(rs, func() error {
+			a.SetValue(b.Value() + 1)
+			return nil
+		}, alien.WithName("b->a"))
+		return nil
+	})
+	c := alien.Signal(rs, 0)
+	alien.Effect(rs, func() error {
+		c.Value()
+		unrelatedRuns++
+		return nil
+	})
+
+	rs.Batch(func() {
+		a.SetValue(100)
+		b.SetValue(100)
+	})
+
+	require.NotNil(t, loopErr)
+	names := []string{}
+	for _, node := range loopErr.Nodes {
+		names = append(names, node.Name())
+	}
+	assert.ElementsMatch(t, []string{"a->b", "b->a"}, names)
+
+	c.SetValue(1)
+	assert.Equal(t, 2, unrelatedRuns)
+}
+
+func TestLoopDetectionAllowsWideFlushes(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithMaxFlushIterations(10))
+
+	src := alien.Signal(rs, 0)
+	runs := 0
+	for i := 0; i < 10; i++ {
+		alien.Effect(rs, func() error {
+			src.Value()
+			runs++
+			return nil
+		})
+	}
+	src.SetValue(1)
+	assert.Equal(t, 20, runs)
+}
+
+func TestLoopDetectionCountsRepeatsNotWidth(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithMaxFlushIterations(10))
+
+	// Ten times more effects than the limit, each running once per write.
+	src := alien.Signal(rs, 0)
+	ran := make([]int, 100)
+	for i := range ran {
+		alien.Effect(rs, func() error {
+			ran[i] = src.Value()
+			return nil
+		})
+	}
+	src.SetValue(1)
+	assert.Equal(t, 1, ran[0])
+	assert.Equal(t, 1, ran[len(ran)-1])
+	src.SetValue(2)
+	assert.Equal(t, 2, ran[len(ran)-1])
+}
+
+func TestMaxFlushIterationsMustBePositive(t *testing.T) {
+	assert.PanicsWithValue(t, "alien: WithMaxFlushIterations(0) needs a positive limit", func() {
+		alien.WithMaxFlushIterations(0)
+	})
+	assert.Panics(t, func() {
+		alien.WithMaxFlushIterations(-1)
+	})
+
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithMaxFlushIterations(1))
+	a := alien.Signal(rs, 0)
+	var seen []int
+	alien.Effect(rs, func() error {
+		seen = append(seen, a.Value())
+		return nil
+	})
+	a.SetValue(1)
+	assert.Equal(t, []int{0, 1}, seen)
+}
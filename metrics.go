@@ -0,0 +1,179 @@
+package alien
+
+import (
+	"expvar"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the counters collected by WithMetrics.
+type Stats struct {
+	Writes         uint64
+	Recomputes     uint64
+	EffectRuns     uint64
+	EffectTime     time.Duration
+	Propagations   uint64
+	QueuedEffects  uint64
+	PeakBatchDepth int64
+	Nodes          []NodeStats
+}
+
+// NodeStats holds the counters of a single node, identified by the same
+// description String() returns.
+type NodeStats struct {
+	Node       string
+	Writes     uint64
+	Recomputes uint64
+	EffectRuns uint64
+	EffectTime time.Duration
+}
+
+type nodeCounters struct {
+	desc string
+	// index is the position in metrics.nodes, or -1 once unregistered.
+	// Guarded by metrics.mu.
+	index      int
+	writes     atomic.Uint64
+	recomputes atomic.Uint64
+	effectRuns atomic.Uint64
+	effectTime atomic.Int64
+}
+
+// metrics is written by the goroutine driving the system and read by
+// whoever calls Stats, typically the expvar HTTP handler, hence the atomics.
+type metrics struct {
+	writes         atomic.Uint64
+	recomputes     atomic.Uint64
+	effectRuns     atomic.Uint64
+	effectTime     atomic.Int64
+	propagations   atomic.Uint64
+	queuedEffects  atomic.Uint64
+	peakBatchDepth atomic.Int64
+
+	mu    sync.Mutex
+	nodes []*nodeCounters
+	// removed counts the nil holes unregister left in nodes.
+	removed int
+}
+
+// WithMetrics enables per-node and system-wide counters, read back with
+// Stats or published with PublishExpvar. A node is reported from its
+// creation until it is stopped or garbage collected.
+func WithMetrics() SystemOption {
+	return func(rs *ReactiveSystem) {
+		rs.metrics = &metrics{}
+	}
+}
+
+// The counters are only held weakly through the node: they are unregistered
+// once the node is collected, which the runtime reports on another
+// goroutine.
+func (m *metrics) register(signal *signal) *nodeCounters {
+	counters := &nodeCounters{desc: signal.String()}
+	m.mu.Lock()
+	counters.index = len(m.nodes)
+	m.nodes = append(m.nodes, counters)
+	m.mu.Unlock()
+	runtime.AddCleanup(signal, m.unregister, counters)
+	return counters
+}
+
+// Removes counters from the report. Holes are compacted once they make up
+// half of nodes, which keeps the order of the remaining nodes.
+func (m *metrics) unregister(counters *nodeCounters) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if counters.index < 0 {
+		return
+	}
+	m.nodes[counters.index] = nil
+	counters.index = -1
+	m.removed++
+	if m.removed*2 < len(m.nodes) {
+		return
+	}
+	nodes := m.nodes[:0]
+	for _, c := range m.nodes {
+		if c != nil {
+			c.index = len(nodes)
+			nodes = append(nodes, c)
+		}
+	}
+	clear(m.nodes[len(nodes):])
+	m.nodes = nodes
+	m.removed = 0
+}
+
+func (m *metrics) recordBatchDepth(depth int) {
+	if d := int64(depth); d > m.peakBatchDepth.Load() {
+		m.peakBatchDepth.Store(d)
+	}
+}
+
+func (m *metrics) recordWrite(signal *signal) {
+	m.writes.Add(1)
+	signal.info.counters.writes.Add(1)
+}
+
+func (m *metrics) recordRecompute(signal *signal) {
+	m.recomputes.Add(1)
+	signal.info.counters.recomputes.Add(1)
+}
+
+func (m *metrics) recordEffectRun(signal *signal, elapsed time.Duration) {
+	m.effectRuns.Add(1)
+	m.effectTime.Add(int64(elapsed))
+	counters := signal.info.counters
+	counters.effectRuns.Add(1)
+	counters.effectTime.Add(int64(elapsed))
+}
+
+// Stats returns the counters collected so far. It is safe to call from any
+// goroutine, but returns the zero value unless the system was created with
+// WithMetrics.
+func (rs *ReactiveSystem) Stats() Stats {
+	m := rs.metrics
+	if m == nil {
+		return Stats{}
+	}
+	stats := Stats{
+		Writes:         m.writes.Load(),
+		Recomputes:     m.recomputes.Load(),
+		EffectRuns:     m.effectRuns.Load(),
+		EffectTime:     time.Duration(m.effectTime.Load()),
+		Propagations:   m.propagations.Load(),
+		QueuedEffects:  m.queuedEffects.Load(),
+		PeakBatchDepth: m.peakBatchDepth.Load(),
+	}
+
+	m.mu.Lock()
+	nodes := make([]*nodeCounters, 0, len(m.nodes)-m.removed)
+	for _, c := range m.nodes {
+		if c != nil {
+			nodes = append(nodes, c)
+		}
+	}
+	m.mu.Unlock()
+
+	stats.Nodes = make([]NodeStats, len(nodes))
+	for i, c := range nodes {
+		stats.Nodes[i] = NodeStats{
+			Node:       c.desc,
+			Writes:     c.writes.Load(),
+			Recomputes: c.recomputes.Load(),
+			EffectRuns: c.effectRuns.Load(),
+			EffectTime: time.Duration(c.effectTime.Load()),
+		}
+	}
+	return stats
+}
+
+// PublishExpvar exposes Stats under the given expvar name. Like
+// expvar.Publish it panics if the name is already in use.
+func (rs *ReactiveSystem) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return rs.Stats()
+	}))
+}
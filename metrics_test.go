@@ -0,0 +1,136 @@
+package alien_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsCounters(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithMetrics())
+
+	a := alien.Signal(rs, 0, alien.WithName("a"))
+	double := alien.Computed(rs, func(oldValue int) int {
+		return a.Value() * 2
+	}, alien.WithName("double"))
+	alien.Effect(rs, func() error {
+		double.Value()
+		return nil
+	}, alien.WithName("render"))
+
+	a.SetValue(1)
+	rs.Batch(func() {
+		rs.Batch(func() {
+			a.SetValue(2)
+			a.SetValue(3)
+		})
+	})
+
+	stats := rs.Stats()
+	assert.Equal(t, uint64(3), stats.Writes)
+	assert.Equal(t, uint64(3), stats.Recomputes)
+	assert.Equal(t, uint64(3), stats.EffectRuns)
+	assert.Equal(t, uint64(3), stats.Propagations)
+	assert.Equal(t, uint64(2), stats.QueuedEffects)
+	assert.Equal(t, int64(2), stats.PeakBatchDepth)
+
+	require.Len(t, stats.Nodes, 3)
+	assert.Equal(t, alien.NodeStats{Node: `signal "a"`, Writes: 3}, stats.Nodes[0])
+	assert.Equal(t, `computed "double"`, stats.Nodes[1].Node)
+	assert.Equal(t, uint64(3), stats.Nodes[1].Recomputes)
+	assert.Equal(t, `effect "render"`, stats.Nodes[2].Node)
+	assert.Equal(t, uint64(3), stats.Nodes[2].EffectRuns)
+	assert.Equal(t, stats.EffectTime, stats.Nodes[2].EffectTime)
+	runtime.KeepAlive(a)
+	runtime.KeepAlive(double)
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	a := alien.Signal(rs, 0)
+	a.SetValue(1)
+	assert.Equal(t, alien.Stats{}, rs.Stats())
+}
+
+func TestMetricsExpvar(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithMetrics())
+	name := fmt.Sprintf("alien_metrics_test_%d", time.Now().UnixNano())
+	rs.PublishExpvar(name)
+
+	a := alien.Signal(rs, 0, alien.WithName("a"))
+	a.SetValue(1)
+
+	v := expvar.Get(name)
+	require.NotNil(t, v)
+	var published alien.Stats
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &published))
+	assert.Equal(t, uint64(1), published.Writes)
+	assert.Equal(t, "signal \"a\"", published.Nodes[0].Node)
+	runtime.KeepAlive(a)
+}
+
+func TestMetricsForgetStoppedEffects(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithMetrics())
+
+	a := alien.Signal(rs, 0, alien.WithName("a"))
+	stops := make([]alien.ErrFn, 10)
+	for i := range stops {
+		stops[i] = alien.Effect(rs, func() error {
+			a.Value()
+			return nil
+		}, alien.WithName(fmt.Sprint("effect ", i)))
+	}
+	require.Len(t, rs.Stats().Nodes, 11)
+
+	for i, stop := range stops {
+		if i != 7 {
+			require.NoError(t, stop())
+		}
+	}
+	a.SetValue(1)
+
+	nodes := rs.Stats().Nodes
+	require.Len(t, nodes, 2)
+	assert.Equal(t, alien.NodeStats{Node: `signal "a"`, Writes: 1}, nodes[0])
+	assert.Equal(t, `effect "effect 7"`, nodes[1].Node)
+	assert.Equal(t, uint64(2), nodes[1].EffectRuns)
+}
+
+func TestMetricsForgetCollectedNodes(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithMetrics())
+
+	kept := alien.Signal(rs, 0, alien.WithName("kept"))
+	for range 100 {
+		alien.Signal(rs, 0)
+	}
+
+	// Cleanups run on their own goroutine after a collection.
+	var nodes []alien.NodeStats
+	for range 50 {
+		runtime.GC()
+		if nodes = rs.Stats().Nodes; len(nodes) == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Len(t, nodes, 1)
+	assert.Equal(t, `signal "kept"`, nodes[0].Node)
+	runtime.KeepAlive(kept)
+}
@@ -0,0 +1,135 @@
+package alien
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const packagePrefix = "github.com/delaneyj/alien-signals-go."
+
+// nodeInfo holds the optional debugging metadata of a node. It is only
+// allocated when a node is given options or the system records call sites,
+// so anonymous nodes pay nothing but a nil pointer.
+type nodeInfo struct {
+	name     string
+	file     string
+	line     int
+	cause    *causeRecord
+	counters *nodeCounters
+	parallel bool
+}
+
+func (rs *ReactiveSystem) initNode(signal *signal, opts []Option) {
+	if len(opts) == 0 && !rs.callSites && !rs.causality && rs.metrics == nil {
+		return
+	}
+	info := &nodeInfo{}
+	for _, opt := range opts {
+		opt(info)
+	}
+	if rs.callSites {
+		info.file, info.line = callerOutsidePackage()
+	}
+	signal.info = info
+	if info.parallel && signal.flags&(fComputed|fEffect) != 0 {
+		signal.flags |= fParallel
+	}
+	if rs.metrics != nil {
+		info.counters = rs.metrics.register(signal)
+	}
+}
+
+// callerOutsidePackage walks up the stack to the first frame that doesn't
+// belong to this package, so nodes created by helpers built on top of
+// Computed or Effect report the user's call site rather than ours.
+func callerOutsidePackage() (string, int) {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			return frame.File, frame.Line
+		}
+		if !more {
+			return frame.File, frame.Line
+		}
+	}
+}
+
+func (s *signal) node() *signal {
+	return s
+}
+
+// Returns the value users know the node by.
+func (s *signal) public() SignalAware {
+	if c, ok := s.ref.(computedAny); ok {
+		return c.public()
+	}
+	return s.ref.(SignalAware)
+}
+
+func (s *signal) kind() string {
+	switch {
+	case s.flags&fEffectScope != 0:
+		return "scope"
+	case s.flags&fEffect != 0:
+		return "effect"
+	case s.flags&fComputed != 0:
+		return "computed"
+	default:
+		return "signal"
+	}
+}
+
+// Name returns the name given with WithName, or an empty string.
+func (s *signal) Name() string {
+	if s.info == nil {
+		return ""
+	}
+	return s.info.name
+}
+
+// String describes the node by kind, name and creation site when known,
+// e.g. `computed "cart.total" (cart.go:42)`.
+func (s *signal) String() string {
+	var sb strings.Builder
+	sb.WriteString(s.kind())
+	if s.info != nil {
+		if s.info.name != "" {
+			fmt.Fprintf(&sb, " %q", s.info.name)
+		}
+		if s.info.file != "" {
+			fmt.Fprintf(&sb, " (%s:%d)", filepath.Base(s.info.file), s.info.line)
+		}
+	}
+	return sb.String()
+}
+
+// NodeError wraps an error returned by a named effect or scope so the
+// reporting node can be identified from the error message alone.
+type NodeError struct {
+	Node SignalAware
+	Err  error
+}
+
+func (e *NodeError) Error() string {
+	return e.Node.String() + ": " + e.Err.Error()
+}
+
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
+
+func (rs *ReactiveSystem) annotate(from *EffectRunner, err error) error {
+	if from.info != nil {
+		return &NodeError{Node: from, Err: err}
+	}
+	return err
+}
+
+func (rs *ReactiveSystem) reportError(from *EffectRunner, err error) {
+	rs.handleError(from.boundary, from, rs.annotate(from, err))
+}
@@ -0,0 +1,59 @@
+package alien_test
+
+import (
+	"errors"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamedNodes(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+
+	price := alien.Signal(rs, 10, alien.WithName("cart.price"))
+	total := alien.Computed(rs, func(oldValue int) int {
+		return price.Value() * 2
+	}, alien.WithName("cart.total"))
+	anonymous := alien.Signal(rs, 0)
+
+	assert.Equal(t, "cart.price", price.Name())
+	assert.Equal(t, `signal "cart.price"`, price.String())
+	assert.Equal(t, `computed "cart.total"`, total.String())
+	assert.Equal(t, "", anonymous.Name())
+	assert.Equal(t, "signal", anonymous.String())
+}
+
+func TestCallSitesAreRecorded(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithCallSites())
+
+	count := alien.Signal(rs, 0, alien.WithName("count"))
+	assert.Regexp(t, `^signal "count" \(node_info_test\.go:\d+\)$`, count.String())
+}
+
+func TestNamedEffectErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+	var reported error
+	var from alien.SignalAware
+	rs := alien.CreateReactiveSystem(func(f alien.SignalAware, err error) {
+		from, reported = f, err
+	})
+
+	alien.Effect(rs, func() error {
+		return errBoom
+	}, alien.WithName("checkout"))
+
+	require.Error(t, reported)
+	assert.ErrorIs(t, reported, errBoom)
+	assert.Equal(t, `effect "checkout": boom`, reported.Error())
+	assert.Equal(t, "checkout", from.Name())
+
+	var nodeErr *alien.NodeError
+	require.ErrorAs(t, reported, &nodeErr)
+	assert.Equal(t, from, nodeErr.Node)
+}
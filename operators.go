@@ -0,0 +1,105 @@
+package alien
+
+// Map derives a value from src with fn. Readers are only notified when the
+// result changes.
+func Map[T any, U comparable](rs *ReactiveSystem, src Readable[T], fn func(T) U, opts ...Option) *ReadonlySignal[U] {
+	return Computed(rs, func(oldValue U) U {
+		return fn(src.Value())
+	}, opts...)
+}
+
+// Filter follows src but only takes on values for which keep returns true,
+// holding on to the last one that passed otherwise. Until a value passes it
+// holds the zero value.
+func Filter[T comparable](rs *ReactiveSystem, src Readable[T], keep func(T) bool, opts ...Option) *ReadonlySignal[T] {
+	return Computed(rs, func(oldValue T) T {
+		if v := src.Value(); keep(v) {
+			return v
+		}
+		return oldValue
+	}, opts...)
+}
+
+// Select follows src but only notifies readers when key of its value
+// changes. In between it keeps returning the value it last notified with.
+func Select[T, K comparable](rs *ReactiveSystem, src Readable[T], key func(T) K, opts ...Option) *ReadonlySignal[T] {
+	var (
+		seen    bool
+		lastKey K
+	)
+	return Computed(rs, func(oldValue T) T {
+		v := src.Value()
+		k := key(v)
+		if seen && k == lastKey {
+			return oldValue
+		}
+		seen, lastKey = true, k
+		return v
+	}, opts...)
+}
+
+// Reduce folds the values src takes on into an accumulator, starting from
+// initial. Like any computed it is evaluated lazily, so it sees the values
+// src holds whenever it is read, not values that were overwritten before
+// anything read it. A computed can also be evaluated again without its
+// sources changing, for instance after losing its last reader, so a value is
+// only folded in when it differs from the one folded before it.
+func Reduce[T, A comparable](rs *ReactiveSystem, src Readable[T], initial A, fn func(acc A, v T) A, opts ...Option) *ReadonlySignal[A] {
+	var (
+		first = true
+		last  T
+	)
+	return Computed(rs, func(oldValue A) A {
+		v := src.Value()
+		if first {
+			first, last = false, v
+			return fn(initial, v)
+		}
+		if v == last {
+			return oldValue
+		}
+		last = v
+		return fn(oldValue, v)
+	}, opts...)
+}
+
+// Tuple2 to Tuple4 hold the values produced by Combine2 to Combine4.
+type Tuple2[A, B comparable] struct {
+	A A
+	B B
+}
+
+type Tuple3[A, B, C comparable] struct {
+	A A
+	B B
+	C C
+}
+
+type Tuple4[A, B, C, D comparable] struct {
+	A A
+	B B
+	C C
+	D D
+}
+
+// Combine2 pairs up the latest values of its sources. Signals only ever hold
+// their latest value, so this also covers what stream libraries call zip.
+func Combine2[A, B comparable](rs *ReactiveSystem, a Readable[A], b Readable[B], opts ...Option) *ReadonlySignal[Tuple2[A, B]] {
+	return Computed(rs, func(oldValue Tuple2[A, B]) Tuple2[A, B] {
+		return Tuple2[A, B]{a.Value(), b.Value()}
+	}, opts...)
+}
+
+// Combine3 is Combine2 for three sources.
+func Combine3[A, B, C comparable](rs *ReactiveSystem, a Readable[A], b Readable[B], c Readable[C], opts ...Option) *ReadonlySignal[Tuple3[A, B, C]] {
+	return Computed(rs, func(oldValue Tuple3[A, B, C]) Tuple3[A, B, C] {
+		return Tuple3[A, B, C]{a.Value(), b.Value(), c.Value()}
+	}, opts...)
+}
+
+// Combine4 is Combine2 for four sources.
+func Combine4[A, B, C, D comparable](rs *ReactiveSystem, a Readable[A], b Readable[B], c Readable[C], d Readable[D], opts ...Option) *ReadonlySignal[Tuple4[A, B, C, D]] {
+	return Computed(rs, func(oldValue Tuple4[A, B, C, D]) Tuple4[A, B, C, D] {
+		return Tuple4[A, B, C, D]{a.Value(), b.Value(), c.Value(), d.Value()}
+	}, opts...)
+}
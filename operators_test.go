@@ -0,0 +1,134 @@
+package alien_test
+
+import (
+	"fmt"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapCombineDiamond(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	n := alien.Signal(rs, 1)
+	double := alien.Map(rs, n, func(v int) int { return v * 2 })
+	label := alien.Map(rs, n, func(v int) string { return fmt.Sprint("n=", v) })
+	both := alien.Combine3(rs, n, double, label)
+
+	var seen []alien.Tuple3[int, int, string]
+	alien.Effect(rs, func() error {
+		seen = append(seen, both.Value())
+		return nil
+	})
+
+	n.SetValue(2)
+	assert.Equal(t, []alien.Tuple3[int, int, string]{
+		{1, 2, "n=1"},
+		{2, 4, "n=2"},
+	}, seen, "every path sees the same update, exactly once")
+}
+
+func TestFilterDiamond(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	n := alien.Signal(rs, 2)
+	even := alien.Filter(rs, n, func(v int) bool { return v%2 == 0 })
+	pair := alien.Combine2(rs, n, even)
+
+	var seen []alien.Tuple2[int, int]
+	alien.Effect(rs, func() error {
+		seen = append(seen, pair.Value())
+		return nil
+	})
+	evenRuns := 0
+	alien.Effect(rs, func() error {
+		evenRuns++
+		even.Value()
+		return nil
+	})
+
+	n.SetValue(3)
+	n.SetValue(4)
+	assert.Equal(t, []alien.Tuple2[int, int]{{2, 2}, {3, 2}, {4, 4}}, seen)
+	assert.Equal(t, 2, evenRuns, "rejected values don't notify")
+}
+
+func TestSelectOnlyNotifiesOnKeyChange(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	type user struct {
+		ID   int
+		Name string
+	}
+	current := alien.Signal(rs, user{1, "ada"})
+	byID := alien.Select(rs, current, func(u user) int { return u.ID })
+	name := alien.Map(rs, current, func(u user) string { return u.Name })
+	both := alien.Combine2(rs, byID, name)
+
+	var seen []string
+	alien.Effect(rs, func() error {
+		v := both.Value()
+		seen = append(seen, fmt.Sprintf("%d:%s/%s", v.A.ID, v.A.Name, v.B))
+		return nil
+	})
+	selectRuns := 0
+	alien.Effect(rs, func() error {
+		selectRuns++
+		byID.Value()
+		return nil
+	})
+
+	current.SetValue(user{1, "ada lovelace"})
+	current.SetValue(user{2, "grace"})
+	assert.Equal(t, []string{"1:ada/ada", "1:ada/ada lovelace", "2:grace/grace"}, seen)
+	assert.Equal(t, 2, selectRuns)
+}
+
+func TestReduceDiamond(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	n := alien.Signal(rs, 1)
+	sum := alien.Reduce(rs, n, 100, func(acc, v int) int { return acc + v })
+	history := alien.Reduce(rs, n, "", func(acc string, v int) string { return acc + fmt.Sprint(v) })
+	both := alien.Combine2(rs, sum, history)
+
+	var seen []alien.Tuple2[int, string]
+	alien.Effect(rs, func() error {
+		seen = append(seen, both.Value())
+		return nil
+	})
+
+	n.SetValue(2)
+	n.SetValue(3)
+	assert.Equal(t, []alien.Tuple2[int, string]{{101, "1"}, {103, "12"}, {106, "123"}}, seen)
+}
+
+func TestReduceFoldsOnlyChanges(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	n := alien.Signal(rs, 1)
+	sum := alien.Reduce(rs, n, 0, func(acc, v int) int { return acc + v })
+
+	var seen []int
+	stop := alien.Effect(rs, func() error {
+		seen = append(seen, sum.Value())
+		return nil
+	})
+	n.SetValue(2)
+	assert.Equal(t, []int{1, 3}, seen)
+
+	// Losing its only reader marks sum dirty; reading it again must not
+	// fold 2 a second time.
+	assert.NoError(t, stop())
+	assert.Equal(t, 3, sum.Value())
+	assert.Equal(t, 3, sum.Value())
+
+	n.SetValue(4)
+	assert.Equal(t, 7, sum.Value())
+}
@@ -0,0 +1,25 @@
+package alien
+
+// Option configures a single node created by Signal, Computed, Effect or
+// EffectScope.
+type Option func(info *nodeInfo)
+
+// WithName attaches a human readable name to a node. Names show up in
+// String(), in errors reported to OnErrorFunc and in any diagnostics built on
+// top of the node.
+func WithName(name string) Option {
+	return func(info *nodeInfo) {
+		info.name = name
+	}
+}
+
+// SystemOption configures a ReactiveSystem at creation time.
+type SystemOption func(rs *ReactiveSystem)
+
+// WithCallSites records the file and line every node was created at. It is
+// meant for debugging, as capturing the caller is comparatively expensive.
+func WithCallSites() SystemOption {
+	return func(rs *ReactiveSystem) {
+		rs.callSites = true
+	}
+}
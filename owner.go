@@ -0,0 +1,127 @@
+package alien
+
+// Root runs fn in a new owner that is detached from whatever is currently
+// running: every computed, effect and scope created inside becomes part of
+// its subtree, and nothing but dispose tears it down. Reads in fn itself are
+// not tracked. The error returned by fn is returned as is.
+func Root(rs *ReactiveSystem, fn func(dispose func()) error, opts ...Option) error {
+	e := &EffectRunner{
+		signal: signal{
+			flags: fEffect | fEffectScope,
+		},
+	}
+	signal := &e.signal
+	signal.ref = e
+	rs.initNode(signal, opts)
+	e.setParent(rs.currentOwner())
+
+	dispose := func() {
+		rs.stopEffect(e)
+	}
+	return rs.runEffectScope(e, signal, func() error {
+		return fn(dispose)
+	})
+}
+
+// Returns the effect or scope that owns nodes created right now. While a
+// computed is being evaluated that is the owner the computed was created in.
+func (rs *ReactiveSystem) currentOwner() *EffectRunner {
+	if sub := rs.activeSub; sub != nil {
+		if sub.flags&fEffect != 0 {
+			return sub.ref.(*EffectRunner)
+		}
+		if sub.flags&fComputed != 0 {
+			return sub.ref.(computedAny).ownedBy()
+		}
+	}
+	if rs.activeScope != nil {
+		return rs.activeScope.ref.(*EffectRunner)
+	}
+	return nil
+}
+
+// Attaches a new effect or scope to the current owner, linking it as a
+// dependency so that stopping or re-running the owner unlinks it too.
+func (rs *ReactiveSystem) adopt(e *EffectRunner) {
+	e.setParent(rs.currentOwner())
+	if rs.activeSub != nil {
+		rs.link(&e.signal, rs.activeSub)
+	} else if rs.activeScope != nil {
+		rs.link(&e.signal, rs.activeScope)
+	}
+}
+
+// Registers fn to run when the current owner re-runs or is stopped. Outside
+// of any owner fn is never called.
+func (rs *ReactiveSystem) onCleanup(fn func()) {
+	if owner := rs.currentOwner(); owner != nil {
+		owner.cleanups = append(owner.cleanups, fn)
+	}
+}
+
+func (e *EffectRunner) setParent(parent *EffectRunner) {
+	e.parent = parent
+	if parent == nil {
+		return
+	}
+	if parent.onErr != nil {
+		e.boundary = parent
+	} else {
+		e.boundary = parent.boundary
+	}
+}
+
+func (rs *ReactiveSystem) stopEffect(e *EffectRunner) {
+	signal := &e.signal
+	rs.stopping++
+	rs.startTracking(signal)
+	rs.endTracking(signal)
+	rs.cleanupEffect(e)
+	rs.stopping--
+	if e.info != nil && e.info.counters != nil {
+		rs.metrics.unregister(e.info.counters)
+	}
+	if rs.idleQueue != nil {
+		rs.drainIdle()
+	}
+}
+
+// Runs fn once nothing is running, tracking, flushing or being torn down,
+// which is right away if the system is already idle. Cleanups use it for
+// work that writes signals or unlinks nodes.
+func (rs *ReactiveSystem) whenIdle(fn func()) {
+	rs.idleQueue = append(rs.idleQueue, fn)
+	rs.drainIdle()
+}
+
+func (rs *ReactiveSystem) drainIdle() {
+	if rs.activeSub != nil || rs.activeScope != nil || rs.flush.depth != 0 || rs.stopping != 0 {
+		return
+	}
+	for len(rs.idleQueue) > 0 {
+		fn := rs.idleQueue[0]
+		rs.idleQueue = rs.idleQueue[1:]
+		fn()
+	}
+	rs.idleQueue = nil
+}
+
+// Releases what an effect or scope accumulated while running: owned
+// computeds are unsubscribed from their dependencies (and recompute if read
+// again), then cleanups run in reverse registration order.
+func (rs *ReactiveSystem) cleanupEffect(e *EffectRunner) {
+	e.provided = nil
+	owned := e.owned
+	e.owned = nil
+	for _, computed := range owned {
+		rs.startTracking(computed)
+		rs.endTracking(computed)
+		computed.flags |= fDirty
+	}
+
+	cleanups := e.cleanups
+	e.cleanups = nil
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+}
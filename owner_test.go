@@ -0,0 +1,272 @@
+package alien_test
+
+import (
+	"errors"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootDisposeTearsDownSubtree(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	count := alien.Signal(rs, 0)
+
+	computeRuns, effectRuns, scopedRuns := 0, 0, 0
+	var dispose func()
+	err := alien.Root(rs, func(d func()) error {
+		dispose = d
+		double := alien.Computed(rs, func(oldValue int) int {
+			computeRuns++
+			return count.Value() * 2
+		})
+		alien.Effect(rs, func() error {
+			effectRuns++
+			double.Value()
+			return nil
+		})
+		alien.EffectScope(rs, func() error {
+			alien.Effect(rs, func() error {
+				scopedRuns++
+				count.Value()
+				return nil
+			})
+			return nil
+		})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 1, 1}, []int{computeRuns, effectRuns, scopedRuns})
+
+	count.SetValue(1)
+	assert.Equal(t, []int{2, 2, 2}, []int{computeRuns, effectRuns, scopedRuns})
+
+	dispose()
+	count.SetValue(2)
+	assert.Equal(t, []int{2, 2, 2}, []int{computeRuns, effectRuns, scopedRuns})
+}
+
+func TestRootDisposesOwnedComputeds(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	count := alien.Signal(rs, 0)
+	runs := 0
+	var double *alien.ReadonlySignal[int]
+	var dispose func()
+	alien.Root(rs, func(d func()) error {
+		dispose = d
+		double = alien.Computed(rs, func(oldValue int) int {
+			runs++
+			return count.Value() * 2
+		})
+		return nil
+	})
+
+	// Read from outside the root, so only ownership ties it to the root.
+	assert.Equal(t, 0, double.Value())
+	dispose()
+	count.SetValue(1)
+	count.SetValue(2)
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, 4, double.Value(), "a disposed computed recomputes on demand")
+}
+
+func TestRootReturnsError(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, "root errors are returned, not reported")
+	})
+	errBoom := errors.New("boom")
+	err := alien.Root(rs, func(dispose func()) error {
+		return errBoom
+	})
+	assert.ErrorIs(t, err, errBoom)
+}
+
+func TestRootIsNotDisposedByEnclosingEffect(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	trigger := alien.Signal(rs, 0)
+	count := alien.Signal(rs, 0)
+
+	roots, innerRuns := 0, 0
+	alien.Effect(rs, func() error {
+		trigger.Value()
+		if roots > 0 {
+			return nil
+		}
+		roots++
+		return alien.Root(rs, func(dispose func()) error {
+			alien.Effect(rs, func() error {
+				innerRuns++
+				count.Value()
+				return nil
+			})
+			return nil
+		})
+	})
+
+	trigger.SetValue(1)
+	count.SetValue(1)
+	assert.Equal(t, 2, innerRuns)
+}
+
+func TestNestedScopesStopTogether(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	count := alien.Signal(rs, 0)
+
+	innerRuns, afterRuns := 0, 0
+	stop := alien.EffectScope(rs, func() error {
+		alien.EffectScope(rs, func() error {
+			alien.Effect(rs, func() error {
+				innerRuns++
+				count.Value()
+				return nil
+			})
+			return nil
+		})
+		// Created after the nested scope returned, so it must still belong to
+		// the outer scope.
+		alien.Effect(rs, func() error {
+			afterRuns++
+			count.Value()
+			return nil
+		})
+		return nil
+	})
+
+	count.SetValue(1)
+	assert.Equal(t, []int{2, 2}, []int{innerRuns, afterRuns})
+
+	stop()
+	count.SetValue(2)
+	assert.Equal(t, []int{2, 2}, []int{innerRuns, afterRuns})
+}
+
+func TestEffectReRunDisposesOwnedComputeds(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	trigger := alien.Signal(rs, 0)
+	count := alien.Signal(rs, 0)
+
+	computeRuns := 0
+	var first *alien.ReadonlySignal[int]
+	alien.Effect(rs, func() error {
+		trigger.Value()
+		c := alien.Computed(rs, func(oldValue int) int {
+			computeRuns++
+			return count.Value()
+		})
+		if first == nil {
+			first = c
+			alien.Effect(rs, func() error {
+				c.Value()
+				return nil
+			})
+		}
+		return nil
+	})
+	assert.Equal(t, 1, computeRuns)
+
+	trigger.SetValue(1)
+	count.SetValue(1)
+	assert.Equal(t, 1, computeRuns, "the computed from the first run was disposed")
+}
+
+// fillLinkPool tears down an effect that read more signals than the system
+// keeps spare links for, so links unlinked afterwards are not recycled.
+func fillLinkPool(rs *alien.ReactiveSystem) {
+	signals := make([]*alien.WriteableSignal[int], 20_000)
+	for i := range signals {
+		signals[i] = alien.Signal(rs, 0)
+	}
+	stop := alien.Effect(rs, func() error {
+		for _, s := range signals {
+			s.Value()
+		}
+		return nil
+	})
+	stop()
+}
+
+func TestRootDisposedByItsEffectSkipsSiblings(t *testing.T) {
+	if debugBuild {
+		t.Skip("fillLinkPool links one effect to 20,000 signals and the alien_debug checker walks its whole deps chain on every link, which takes over a minute")
+	}
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	s := alien.Signal(rs, 0)
+
+	firstRuns, secondRuns := 0, 0
+	alien.Root(rs, func(dispose func()) error {
+		alien.Effect(rs, func() error {
+			firstRuns++
+			if s.Value() > 0 {
+				dispose()
+			}
+			return nil
+		})
+		alien.Effect(rs, func() error {
+			secondRuns++
+			s.Value()
+			return nil
+		})
+		return nil
+	})
+	fillLinkPool(rs)
+
+	// Both effects are pending when the first one disposes the root, which
+	// disposes the second one before the flush gets to it.
+	s.SetValue(1)
+	assert.Equal(t, []int{2, 1}, []int{firstRuns, secondRuns})
+	s.SetValue(2)
+	assert.Equal(t, []int{2, 1}, []int{firstRuns, secondRuns})
+}
+
+func TestRootDisposedByItsEffectWithRecycledLinks(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	// Leaves spare links behind for the ones released by dispose to be
+	// chained to.
+	signals := make([]*alien.WriteableSignal[int], 20)
+	for i := range signals {
+		signals[i] = alien.Signal(rs, 0)
+	}
+	stop := alien.Effect(rs, func() error {
+		for _, s := range signals {
+			s.Value()
+		}
+		return nil
+	})
+	stop()
+
+	s := alien.Signal(rs, 0)
+	firstRuns, secondRuns := 0, 0
+	alien.Root(rs, func(dispose func()) error {
+		alien.Effect(rs, func() error {
+			firstRuns++
+			if s.Value() > 0 {
+				dispose()
+			}
+			return nil
+		})
+		alien.Effect(rs, func() error {
+			secondRuns++
+			s.Value()
+			return nil
+		})
+		return nil
+	})
+
+	assert.NotPanics(t, func() { s.SetValue(1) })
+	s.SetValue(2)
+	assert.Equal(t, []int{2, 1}, []int{firstRuns, secondRuns})
+}
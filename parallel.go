@@ -0,0 +1,394 @@
+package alien
+
+import (
+	"runtime/debug"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithParallel lets a computed or effect refresh its dirty dependencies on
+// the system's workers (see WithWorkers) before it reads them. Dependencies
+// are split into groups that share no stale upstream computed; each group is
+// refreshed on one goroutine, upstream first, so every computed is still
+// evaluated at most once and nothing observes a half updated graph.
+//
+// Only getters of computeds that already ran can be refreshed this way, and
+// they must be pure: they may only read signals and computeds, from the
+// goroutine they are called on. A getter that reads different ones than on
+// its previous run, say behind a condition, is evaluated again with tracking
+// on the calling goroutine, together with whatever in its group depends on
+// it. Grouping the graph costs more than evaluating cheap getters, so this
+// pays off for expensive ones only.
+func WithParallel() Option {
+	return func(info *nodeInfo) {
+		info.parallel = true
+	}
+}
+
+// WithWorkers sets how many goroutines refresh dependencies of nodes created
+// with WithParallel. With fewer than two, which is the default, they are
+// refreshed one after another like any other node.
+func WithWorkers(n int) SystemOption {
+	return func(rs *ReactiveSystem) {
+		rs.workers = n
+	}
+}
+
+// A set of stale computeds, in topological order, that no other group
+// reads from or feeds into.
+type parallelGroup struct {
+	nodes []*signal
+	// done counts the nodes handled before the group finished or panicked.
+	done     int
+	results  []parallelResult
+	state    map[*signal]refreshState
+	panicked *workerPanic
+}
+
+type parallelResult struct {
+	evaluated bool
+	changed   bool
+	// retrack is set when the getter read different deps than on its
+	// previous run, deferred when the node depends on such a getter. Either
+	// way the node is left as it was for the owning goroutine.
+	retrack  bool
+	deferred bool
+	elapsed  time.Duration
+}
+
+type refreshState uint8
+
+const (
+	refreshUnchanged refreshState = iota
+	refreshChanged
+	refreshDeferred
+)
+
+// parallelWorker follows the reads of the getter a worker is running,
+// comparing them with the deps the getter read on its previous run.
+type parallelWorker struct {
+	// id is the readerID of the worker, as reads carry nothing else that
+	// tells which getter they were made for.
+	id     atomic.Uint64
+	node   *signal
+	cursor *link
+	prev   *link
+	// mismatch is set once a read didn't follow the recorded deps.
+	mismatch bool
+}
+
+func (w *parallelWorker) begin(node *signal) {
+	w.node, w.cursor, w.prev, w.mismatch = node, node.deps, nil, false
+}
+
+// Reports whether the getter read exactly the deps it read before.
+func (w *parallelWorker) end() bool {
+	matched := !w.mismatch && w.cursor == nil
+	w.node, w.cursor, w.prev = nil, nil, nil
+	return matched
+}
+
+func (w *parallelWorker) read(dep *signal) {
+	if w.node == nil {
+		return
+	}
+	if l := w.cursor; l != nil && l.dep == dep {
+		w.prev, w.cursor = l, l.nextDep
+		return
+	}
+	// Reading the dep just read again is what link skips as well.
+	if w.prev == nil || w.prev.dep != dep {
+		w.mismatch = true
+	}
+}
+
+// Hands a read made while workers refresh computeds to the worker it was
+// made on. Reads from other goroutines can't be told apart and are ignored.
+func (rs *ReactiveSystem) parallelRead(dep *signal) {
+	id := readerID()
+	for i := range rs.readers {
+		if w := &rs.readers[i]; w.id.Load() == id {
+			w.read(dep)
+			return
+		}
+	}
+}
+
+// workerPanic carries a panic raised on a worker back to the owning
+// goroutine, together with the worker's stack.
+type workerPanic struct {
+	value any
+	stack []byte
+}
+
+// parallelScratch holds what grouping the stale dependencies of a node needs,
+// so that it can be reused from one refresh to the next. Groups are merged
+// with a union-find over the ids handed out in the order dependencies are
+// found.
+type parallelScratch struct {
+	groupOf map[*signal]int
+	nodes   [][]*signal
+	parent  []int
+	// groupOfRoot maps a root id to its index in groups, or -1.
+	groupOfRoot []int
+	groups      []*parallelGroup
+	workers     []parallelWorker
+	retrack     []*signal
+}
+
+// Refreshes the stale computeds sub depends on in parallel, when there is
+// more than one independent group of them. Whatever can't be refreshed this
+// way is left for the regular serial path.
+//
+// A panic raised by a getter is raised again on the owning goroutine once
+// every worker is done, with its original value, so that it surfaces as if
+// the getter had been evaluated serially. An error boundary that catches it
+// still gets the worker's stack.
+func (rs *ReactiveSystem) refreshParallel(sub *signal) {
+	// A tracer called below may refresh another node, so the scratch is
+	// taken for the duration of the refresh.
+	scratch := rs.scratch
+	if scratch == nil {
+		scratch = &parallelScratch{groupOf: map[*signal]int{}}
+	}
+	rs.scratch = nil
+	defer func() {
+		rs.scratch = scratch
+	}()
+
+	groups := scratch.group(sub)
+	if len(groups) < 2 {
+		return
+	}
+
+	n := min(rs.workers, len(groups))
+	if cap(scratch.workers) < n {
+		scratch.workers = make([]parallelWorker, n)
+	}
+	workers := scratch.workers[:n]
+	for i := range workers {
+		workers[i].id.Store(0)
+	}
+
+	prevSub, prevScope := rs.activeSub, rs.activeScope
+	rs.activeSub, rs.activeScope = nil, nil
+	rs.parallel, rs.readers = true, workers
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+	)
+	for i := range workers {
+		wg.Add(1)
+		go func(w *parallelWorker) {
+			defer wg.Done()
+			lockReader()
+			defer unlockReader()
+			w.id.Store(readerID())
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= len(groups) {
+					return
+				}
+				rs.refreshGroup(groups[i], w)
+			}
+		}(&workers[i])
+	}
+	wg.Wait()
+	rs.parallel, rs.readers = false, nil
+	rs.activeSub, rs.activeScope = prevSub, prevScope
+
+	// Back on the owning goroutine, apply what the workers did the same way
+	// checkDirty would have, upstream first.
+	var panicked *workerPanic
+	retrack := scratch.retrack[:0]
+	for _, g := range groups {
+		for i, node := range g.nodes[:g.done] {
+			r := g.results[i]
+			if r.retrack {
+				retrack = append(retrack, node)
+			}
+			if r.retrack || r.deferred {
+				continue
+			}
+			node.flags &^= fDirty | fPendingComputed
+			if !r.evaluated {
+				continue
+			}
+			if rs.metrics != nil {
+				rs.metrics.recordRecompute(node)
+			}
+			if r.changed && rs.causality {
+				rs.recordComputedChange(node)
+			}
+			if rs.tracer != nil {
+				rs.tracer.ComputedRecompute(node.public(), r.elapsed, r.changed)
+			}
+			if r.changed && node.subs != nil {
+				rs.shallowPropagate(node.subs)
+			}
+		}
+		if panicked == nil {
+			panicked = g.panicked
+		}
+		g.panicked = nil
+	}
+	scratch.retrack = retrack[:0]
+	if panicked != nil {
+		if rs.recovering > 0 {
+			panic(panicked)
+		}
+		panic(panicked.value)
+	}
+
+	// Getters that switched deps may have read stale values. They don't
+	// depend on each other, and what depends on them was left dirty or
+	// pending for checkDirty.
+	for _, node := range retrack {
+		if updateComputed(rs, node) && node.subs != nil {
+			rs.shallowPropagate(node.subs)
+		}
+	}
+	clear(retrack)
+}
+
+// Collects the stale computeds upstream of sub's dependencies, merging the
+// closures of dependencies that share any of them. Returns nil if any of
+// them can't be refreshed without tracking. The groups are only valid until
+// the next call.
+func (s *parallelScratch) group(sub *signal) []*parallelGroup {
+	clear(s.groupOf)
+	s.nodes = s.nodes[:0]
+	s.parent = s.parent[:0]
+
+	for l := sub.deps; l != nil; l = l.nextDep {
+		dep := l.dep
+		if !stale(dep) {
+			continue
+		}
+		if _, ok := s.groupOf[dep]; ok {
+			continue
+		}
+		id := len(s.nodes)
+		if id < cap(s.nodes) {
+			s.nodes = s.nodes[:id+1]
+			s.nodes[id] = s.nodes[id][:0]
+		} else {
+			s.nodes = append(s.nodes, nil)
+		}
+		s.parent = append(s.parent, id)
+		if !s.visit(dep, id) {
+			return nil
+		}
+	}
+
+	// Merged groups are concatenated in the order they were found, which
+	// keeps them topologically sorted: a group only reaches into groups found
+	// before it.
+	s.groupOfRoot = s.groupOfRoot[:0]
+	for range s.nodes {
+		s.groupOfRoot = append(s.groupOfRoot, -1)
+	}
+	n := 0
+	for id, nodes := range s.nodes {
+		root := s.find(id)
+		i := s.groupOfRoot[root]
+		if i < 0 {
+			i = n
+			n++
+			s.groupOfRoot[root] = i
+			if i == len(s.groups) {
+				s.groups = append(s.groups, &parallelGroup{state: map[*signal]refreshState{}})
+			}
+			s.groups[i].nodes = s.groups[i].nodes[:0]
+		}
+		g := s.groups[i]
+		g.nodes = append(g.nodes, nodes...)
+	}
+	return s.groups[:n]
+}
+
+func (s *parallelScratch) find(id int) int {
+	for s.parent[id] != id {
+		s.parent[id] = s.parent[s.parent[id]]
+		id = s.parent[id]
+	}
+	return id
+}
+
+func (s *parallelScratch) visit(node *signal, id int) bool {
+	if other, ok := s.groupOf[node]; ok {
+		if root := s.find(other); root != id {
+			s.parent[root] = id
+		}
+		return true
+	}
+	flags := node.flags
+	if flags&fTracking != 0 || (flags&fDirty != 0 && node.deps == nil) {
+		return false
+	}
+	s.groupOf[node] = id
+	for l := node.deps; l != nil; l = l.nextDep {
+		if stale(l.dep) && !s.visit(l.dep, id) {
+			return false
+		}
+	}
+	s.nodes[id] = append(s.nodes[id], node)
+	return true
+}
+
+func stale(node *signal) bool {
+	flags := node.flags
+	return flags&fComputed != 0 && flags&(fDirty|fPendingComputed) != 0
+}
+
+// Runs on w. Nodes are only re-evaluated when they are dirty or one of their
+// dependencies changed, which is what checkDirty decides serially.
+func (rs *ReactiveSystem) refreshGroup(g *parallelGroup, w *parallelWorker) {
+	g.done = 0
+	g.results = slices.Grow(g.results[:0], len(g.nodes))[:len(g.nodes)]
+	clear(g.results)
+	clear(g.state)
+	defer func() {
+		if r := recover(); r != nil {
+			w.end()
+			g.panicked = &workerPanic{value: r, stack: debug.Stack()}
+		}
+	}()
+
+	for i, node := range g.nodes {
+		r := &g.results[i]
+		evaluate := node.flags&fDirty != 0
+		for l := node.deps; l != nil && !r.deferred; l = l.nextDep {
+			switch g.state[l.dep] {
+			case refreshChanged:
+				evaluate = true
+			case refreshDeferred:
+				r.deferred = true
+			}
+		}
+		if r.deferred {
+			g.state[node] = refreshDeferred
+		} else if evaluate {
+			var start time.Time
+			if rs.tracer != nil {
+				start = time.Now()
+			}
+			changed, kept := node.ref.(computedAny).casOn(w)
+			if !kept {
+				r.retrack = true
+				g.state[node] = refreshDeferred
+			} else {
+				r.evaluated, r.changed = true, changed
+				if changed {
+					g.state[node] = refreshChanged
+				}
+			}
+			if rs.tracer != nil {
+				r.elapsed = time.Since(start)
+			}
+		}
+		g.done = i + 1
+	}
+}
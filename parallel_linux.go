@@ -0,0 +1,23 @@
+//go:build linux
+
+package alien
+
+import (
+	"runtime"
+	"syscall"
+)
+
+// Workers are pinned to their threads while they refresh, so that the
+// thread id tells reads made on them apart. It is far cheaper to get than
+// the goroutine id.
+func lockReader() {
+	runtime.LockOSThread()
+}
+
+func unlockReader() {
+	runtime.UnlockOSThread()
+}
+
+func readerID() uint64 {
+	return uint64(syscall.Gettid())
+}
@@ -0,0 +1,30 @@
+//go:build !linux
+
+package alien
+
+import (
+	"runtime"
+	"sync"
+)
+
+func lockReader() {}
+
+func unlockReader() {}
+
+// readerID returns the id of the calling goroutine, as printed at the top of
+// its stack trace. Go has no cheaper portable way to tell goroutines apart.
+func readerID() uint64 {
+	buf := stackBufs.Get().(*[64]byte)
+	b := buf[:runtime.Stack(buf[:], false)]
+	var id uint64
+	for _, c := range b[len("goroutine "):] {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	stackBufs.Put(buf)
+	return id
+}
+
+var stackBufs = sync.Pool{New: func() any { return new([64]byte) }}
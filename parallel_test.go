@@ -0,0 +1,264 @@
+package alien_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildChains hangs width chains of depth computeds off src and counts how
+// often each computed is evaluated.
+func buildChains(rs *alien.ReactiveSystem, src alien.Readable[int], width, depth int, evals *atomic.Int64) []alien.Readable[int] {
+	tails := make([]alien.Readable[int], width)
+	for i := range tails {
+		var last = src
+		for range depth {
+			prev := last
+			last = alien.Computed(rs, func(oldValue int) int {
+				evals.Add(1)
+				return prev.Value() + 1
+			})
+		}
+		tails[i] = last
+	}
+	return tails
+}
+
+func TestParallelRefreshMatchesSerial(t *testing.T) {
+	const width, depth = 16, 5
+	run := func(opts ...alien.SystemOption) ([][]int, int64) {
+		rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+			assert.FailNow(t, err.Error())
+		}, opts...)
+		src := alien.Signal(rs, 0)
+		var evals atomic.Int64
+		tails := buildChains(rs, src, width, depth, &evals)
+
+		var seen [][]int
+		alien.Effect(rs, func() error {
+			values := make([]int, len(tails))
+			for i, tail := range tails {
+				values[i] = tail.Value()
+			}
+			seen = append(seen, values)
+			return nil
+		}, alien.WithParallel())
+
+		for i := 1; i <= 3; i++ {
+			src.SetValue(i * 10)
+		}
+		return seen, evals.Load()
+	}
+
+	serial, serialEvals := run()
+	parallel, parallelEvals := run(alien.WithWorkers(4))
+	assert.Equal(t, serial, parallel)
+	assert.Equal(t, serialEvals, parallelEvals, "every computed is evaluated once per write")
+	assert.Equal(t, int64(4*width*depth), parallelEvals)
+	for _, values := range parallel {
+		for _, v := range values[1:] {
+			assert.Equal(t, values[0], v, "no chain is seen half updated")
+		}
+	}
+}
+
+func TestParallelRefreshRunsConcurrently(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithWorkers(4))
+	src := alien.Signal(rs, 0)
+
+	// Each branch waits for the other to start, which only works if they
+	// run at the same time.
+	var started sync.WaitGroup
+	var met atomic.Bool
+	branch := func() *alien.ReadonlySignal[int] {
+		return alien.Computed(rs, func(oldValue int) int {
+			v := src.Value()
+			if v > 0 {
+				started.Done()
+				done := make(chan struct{})
+				go func() {
+					started.Wait()
+					close(done)
+				}()
+				select {
+				case <-done:
+					met.Store(true)
+				case <-time.After(time.Second):
+				}
+			}
+			return v
+		})
+	}
+	left, right := branch(), branch()
+	sum := alien.Computed(rs, func(oldValue int) int {
+		return left.Value() + right.Value()
+	}, alien.WithParallel())
+	sum.Value()
+
+	started.Add(2)
+	src.SetValue(1)
+	assert.Equal(t, 2, sum.Value())
+	assert.True(t, met.Load())
+}
+
+func TestParallelRefreshSharedUpstream(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithWorkers(4))
+	src := alien.Signal(rs, 1)
+	other := alien.Signal(rs, 1)
+
+	var sharedEvals atomic.Int64
+	shared := alien.Computed(rs, func(oldValue int) int {
+		sharedEvals.Add(1)
+		return src.Value() * 2
+	})
+	// a and b share an upstream computed, so they have to be refreshed by
+	// the same worker; c is independent.
+	a := alien.Computed(rs, func(oldValue int) int { return shared.Value() + 1 })
+	b := alien.Computed(rs, func(oldValue int) int { return shared.Value() + 2 })
+	c := alien.Computed(rs, func(oldValue int) int { return src.Value() + other.Value() })
+
+	var seen []int
+	alien.Effect(rs, func() error {
+		seen = append(seen, a.Value()+b.Value()+c.Value())
+		return nil
+	}, alien.WithParallel())
+
+	src.SetValue(2)
+	other.SetValue(5)
+	assert.Equal(t, []int{3 + 4 + 2, 5 + 6 + 3, 5 + 6 + 7}, seen)
+	assert.Equal(t, int64(2), sharedEvals.Load())
+}
+
+func TestParallelRefreshConditionalReads(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithWorkers(4))
+	flag := alien.Signal(rs, true)
+	a := alien.Signal(rs, 1)
+	base := alien.Signal(rs, 50)
+	// b is only read once flag flips, and is stale by then.
+	b := alien.Computed(rs, func(oldValue int) int { return base.Value() * 2 })
+
+	// Each chain switches what it reads; the computed after it has to see
+	// the switched value too.
+	var evals atomic.Int64
+	tails := make([]*alien.ReadonlySignal[int], 4)
+	for i := range tails {
+		cond := alien.Computed(rs, func(oldValue int) int {
+			evals.Add(1)
+			if flag.Value() {
+				return a.Value() + i
+			}
+			return b.Value() + i
+		})
+		tails[i] = alien.Computed(rs, func(oldValue int) int { return cond.Value() * 2 })
+	}
+
+	var seen []int
+	alien.Effect(rs, func() error {
+		sum := 0
+		for _, tail := range tails {
+			sum += tail.Value()
+		}
+		seen = append(seen, sum)
+		return nil
+	}, alien.WithParallel())
+
+	base.SetValue(60)
+	flag.SetValue(false)
+	base.SetValue(100)
+	// No longer read by any chain.
+	a.SetValue(2)
+	assert.Equal(t, []int{2 * (4*1 + 6), 2 * (4*120 + 6), 2 * (4*200 + 6)}, seen)
+	// The switch is evaluated once on the workers and once more with
+	// tracking.
+	assert.Equal(t, int64(4+2*4+4), evals.Load())
+}
+
+func TestParallelRefreshPanics(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithWorkers(4))
+	src := alien.Signal(rs, 1)
+	errBroken := errors.New("broken")
+
+	evals := map[int]*atomic.Int64{0: {}, 1: {}}
+	branch := func(i int) *alien.ReadonlySignal[int] {
+		return alien.Computed(rs, func(oldValue int) int {
+			evals[i].Add(1)
+			v := src.Value()
+			if i == 1 && v == 2 {
+				panic(errBroken)
+			}
+			return v * 10
+		})
+	}
+	left, right := branch(0), branch(1)
+	sum := alien.Computed(rs, func(oldValue int) int {
+		return left.Value() + right.Value()
+	}, alien.WithParallel())
+	assert.Equal(t, 20, sum.Value())
+
+	// Without a boundary the panic surfaces as it would serially.
+	src.SetValue(2)
+	assert.PanicsWithValue(t, errBroken, func() { sum.Value() })
+
+	// The branch that finished keeps its value; the failed one is retried.
+	src.SetValue(3)
+	assert.Equal(t, 60, sum.Value())
+	assert.Equal(t, int64(3), evals[0].Load())
+	assert.Equal(t, int64(3), evals[1].Load())
+}
+
+func TestParallelRefreshPanicsReachBoundary(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, "should be caught by the boundary", err.Error())
+	}, alien.WithWorkers(4))
+	src := alien.Signal(rs, 1)
+	errBroken := errors.New("broken")
+
+	branch := func(i int) *alien.ReadonlySignal[int] {
+		return alien.Computed(rs, func(oldValue int) int {
+			v := src.Value()
+			if i == 1 && v == 2 {
+				panic(errBroken)
+			}
+			return v * 10
+		})
+	}
+	left, right := branch(0), branch(1)
+
+	var caught []error
+	var seen []int
+	alien.EffectScopeWithErrorHandler(rs, func() error {
+		alien.Effect(rs, func() error {
+			seen = append(seen, left.Value()+right.Value())
+			return nil
+		}, alien.WithParallel())
+		return nil
+	}, func(from alien.SignalAware, err error) alien.ErrorAction {
+		caught = append(caught, err)
+		return alien.RecoverError
+	})
+
+	assert.NotPanics(t, func() { src.SetValue(2) })
+	require.Len(t, caught, 1)
+	var panicErr *alien.PanicError
+	require.ErrorAs(t, caught[0], &panicErr)
+	assert.Equal(t, errBroken, panicErr.Value)
+	assert.Contains(t, string(panicErr.Stack), "parallel_test.go", "the worker's stack")
+
+	src.SetValue(3)
+	assert.Equal(t, []int{20, 60}, seen)
+	assert.Len(t, caught, 1)
+}
@@ -1,40 +1,103 @@
 package alien
 
+import "time"
+
 type OnErrorFunc func(from SignalAware, err error)
 
 type ReactiveSystem struct {
-	batchDepth        int
-	activeSub         *signal
-	queuedEffects     *OneWayLink_signal
-	queuedEffectsTail *OneWayLink_signal
+	batchDepth int
+	activeSub  *signal
+	// queuedEffects[queuedHead:] are the effects waiting to be flushed. The
+	// slice is rewound once it has been drained, so it is reused across
+	// flushes.
+	queuedEffects []*signal
+	queuedHead    int
+	// Scratch stacks of propagate and checkDirty. checkDirty may re-enter
+	// through a computed getter, so every call only touches the part above
+	// the length it found on entry.
+	propagateStack  []*link
+	checkDirtyStack []*link
 
 	activeScope *signal
 	onError     OnErrorFunc
 	pauseStack  []*signal
-}
 
-type SignalAware interface {
-	isSignalAware()
+	callSites   bool
+	causality   bool
+	causeSeq    uint64
+	tracer      Tracer
+	batchStarts []time.Time
+	metrics     *metrics
+
+	maxFlushIterations int
+	flush              flushState
+
+	computing []*signal
+	cycle     *CycleError
+
+	provided  map[any]any
+	idleQueue []func()
+	stopping  int
+
+	dispatcher dispatcher
+	clock      Clock
+
+	// Links dropped by clearTracking, chained through nextDep, waiting to be
+	// reused by linkNewDep. At most maxPooledLinks are kept.
+	linkPool     *link
+	linkPoolSize int
+
+	weakComputeds bool
+	dropped       droppedComputeds
+
+	debug debugState
+
+	workers int
+	// parallel is set while workers refresh computeds; reads then return
+	// values as they are instead of updating or tracking anything, and are
+	// only checked against the deps in readers.
+	parallel bool
+	readers  []parallelWorker
+	// scratch holds the buffers of refreshParallel between refreshes.
+	scratch *parallelScratch
+	// recovering counts the invoke calls on the stack that hand panics to a
+	// boundary.
+	recovering int
 }
 
-type OneWayLink_signal struct {
-	target *signal
-	linked *OneWayLink_signal
-}
+// maxPooledLinks bounds the link free list, so that tearing down a large
+// graph doesn't pin its links for the lifetime of the system.
+const maxPooledLinks = 1 << 14
 
-type OneWayLink_link struct {
-	target *link
-	linked *OneWayLink_link
+type SignalAware interface {
+	isSignalAware()
+	node() *signal
+	Name() string
+	String() string
 }
 
-func CreateReactiveSystem(onError OnErrorFunc) *ReactiveSystem {
-	rs := &ReactiveSystem{onError: onError}
+func CreateReactiveSystem(onError OnErrorFunc, opts ...SystemOption) *ReactiveSystem {
+	rs := &ReactiveSystem{
+		onError:            onError,
+		maxFlushIterations: DefaultMaxFlushIterations,
+		clock:              systemClock{},
+	}
+	for _, opt := range opts {
+		opt(rs)
+	}
 
 	return rs
 }
 
 func (rs *ReactiveSystem) StartBatch() {
 	rs.batchDepth++
+	if rs.metrics != nil {
+		rs.metrics.recordBatchDepth(rs.batchDepth)
+	}
+	if rs.tracer != nil {
+		rs.batchStarts = append(rs.batchStarts, time.Now())
+		rs.tracer.BatchStart(rs.batchDepth)
+	}
 }
 
 func (rs *ReactiveSystem) EndBatch() {
@@ -42,6 +105,12 @@ func (rs *ReactiveSystem) EndBatch() {
 	if rs.batchDepth == 0 {
 		rs.processEffectNotifications()
 	}
+	if rs.tracer != nil {
+		lastIdx := len(rs.batchStarts) - 1
+		start := rs.batchStarts[lastIdx]
+		rs.batchStarts = rs.batchStarts[:lastIdx]
+		rs.tracer.BatchEnd(rs.batchDepth+1, time.Since(start))
+	}
 }
 
 func (rs *ReactiveSystem) Batch(cb func()) {
@@ -87,63 +156,91 @@ func (rs *ReactiveSystem) updateDirtyFlag(sub *signal, flags subscriberFlags) bo
 // @param link - The starting link representing a sequence of pending computeds.
 // @returns `true` if a computed was updated, otherwise `false`.
 func (rs *ReactiveSystem) checkDirty(current *link) bool {
-	prevLinks := (*OneWayLink_link)(nil)
+	// prevLinks lives in checkDirtyStack above base. The deferred reset also
+	// runs when a getter panics, so callers further up find the stack as
+	// they left it.
+	base := len(rs.checkDirtyStack)
+	defer func() {
+		rs.checkDirtyStack = rs.checkDirtyStack[:base]
+	}()
 	checkDepth := 0
+	dirty := false
 
 top:
 	for {
+		dirty = false
 		dep := current.dep
 		depFlags := dep.flags
-		if depFlags&(fComputed|fDirty) == fComputed|fDirty {
+
+		if current.sub.flags&fDirty != 0 {
+			// A sibling's shallowPropagate already marked this level dirty.
+			dirty = true
+		} else if depFlags&(fComputed|fDirty) == fComputed|fDirty {
 			if updateComputed(rs, dep) {
 				subs := dep.subs
 				if subs.nextSub != nil {
 					rs.shallowPropagate(subs)
 				}
-				for checkDepth != 0 {
-					checkDepth--
-					computed := current.sub
-					firstSub := computed.subs
-
-					if updateComputed(rs, current.sub) {
-						if firstSub.nextSub != nil {
-							rs.shallowPropagate(firstSub)
-							current = prevLinks.target
-							prevLinks = prevLinks.linked
-						} else {
-							current = firstSub
-						}
-						continue
-					}
-
-					if firstSub.nextSub != nil {
-						if current = prevLinks.target.nextDep; current == nil {
-							return false
-						}
-						prevLinks = prevLinks.linked
-						continue top
-					}
-
-					return false
-				}
-				return true
+				dirty = true
 			}
 		} else if depFlags&(fComputed|fPendingComputed) == fComputed|fPendingComputed {
-			dep.flags = depFlags & ^fPendingComputed
-			if current.nextSub != nil && current.prevSub != nil {
-				prevLinks = &OneWayLink_link{target: current, linked: prevLinks}
+			if current.nextSub != nil || current.prevSub != nil {
+				rs.checkDirtyStack = append(rs.checkDirtyStack, current)
 			}
 			checkDepth++
 			current = dep.deps
 			continue
 		}
 
-		if current = current.nextDep; current == nil {
-			return false
+		if !dirty && current.nextDep != nil {
+			current = current.nextDep
+			continue
+		}
+
+		// Walk back up, updating each pending computed whose deps changed
+		// and moving on to the next dep of those that did not.
+		for checkDepth != 0 {
+			checkDepth--
+			computed := current.sub
+			firstSub := computed.subs
+
+			if dirty {
+				if updateComputed(rs, computed) {
+					if firstSub.nextSub != nil {
+						current = rs.popCheckDirty()
+						rs.shallowPropagate(firstSub)
+					} else {
+						current = firstSub
+					}
+					continue
+				}
+			} else {
+				computed.flags &^= fPendingComputed
+			}
+
+			if firstSub.nextSub != nil {
+				current = rs.popCheckDirty()
+			} else {
+				current = firstSub
+			}
+			if current.nextDep != nil {
+				current = current.nextDep
+				continue top
+			}
+			dirty = false
 		}
+
+		return dirty
 	}
 }
 
+func (rs *ReactiveSystem) popCheckDirty() *link {
+	last := len(rs.checkDirtyStack) - 1
+	l := rs.checkDirtyStack[last]
+	rs.checkDirtyStack = rs.checkDirtyStack[:last]
+	return l
+}
+
 // Links a given dependency and subscriber if they are not already linked.
 //
 // @param dep - The dependency to be linked.
@@ -215,10 +312,19 @@ func (rs *ReactiveSystem) isValidLink(checkLink *link, sub *signal) bool {
 // @param depsTail - The current tail link in the subscriber's chain.
 // @returns The newly created link object.
 func (rs *ReactiveSystem) linkNewDep(dep *signal, sub *signal, nextDep, depsTail *link) *link {
-	newLink := &link{
-		dep:     dep,
-		sub:     sub,
-		nextDep: nextDep,
+	newLink := rs.linkPool
+	if newLink != nil {
+		rs.linkPool = newLink.nextDep
+		rs.linkPoolSize--
+		newLink.dep = dep
+		newLink.sub = sub
+		newLink.nextDep = nextDep
+	} else {
+		newLink = &link{
+			dep:     dep,
+			sub:     sub,
+			nextDep: nextDep,
+		}
 	}
 
 	if depsTail == nil {
@@ -237,6 +343,12 @@ func (rs *ReactiveSystem) linkNewDep(dep *signal, sub *signal, nextDep, depsTail
 
 	sub.depsTail = newLink
 	dep.subsTail = newLink
+	rs.debugCheck(dep)
+	rs.debugCheck(sub)
+
+	if rs.tracer != nil {
+		rs.tracer.Link(dep.public(), sub.public())
+	}
 
 	return newLink
 }
@@ -249,8 +361,13 @@ func (rs *ReactiveSystem) linkNewDep(dep *signal, sub *signal, nextDep, depsTail
 //
 // @param link - The starting link from which propagation begins.
 func (rs *ReactiveSystem) propagate(current *link) {
+	if rs.metrics != nil {
+		rs.metrics.propagations.Add(1)
+	}
 	next := current.nextSub
-	branchs := (*OneWayLink_link)(nil)
+	// propagate never calls back into user code, so it can't re-enter and
+	// may always start from an empty stack.
+	branchs := rs.propagateStack[:0]
 	branchDepth := 0
 	targetFlag := fDirty
 
@@ -276,7 +393,7 @@ top:
 			if subSubs != nil {
 				current = subSubs
 				if subSubs.nextSub != nil {
-					branchs = &OneWayLink_link{target: next, linked: branchs}
+					branchs = append(branchs, next)
 					branchDepth++
 					next = current.nextSub
 					targetFlag = fPendingComputed
@@ -290,24 +407,12 @@ top:
 				continue
 			}
 			if flags&fEffect != 0 {
-				if rs.queuedEffectsTail != nil {
-					rs.queuedEffectsTail.linked = &OneWayLink_signal{target: sub}
-					rs.queuedEffectsTail = rs.queuedEffectsTail.linked
-				} else {
-					rs.queuedEffects = &OneWayLink_signal{target: sub}
-					rs.queuedEffectsTail = rs.queuedEffects
-				}
+				rs.queueEffect(sub)
 			}
 		} else if flags&(fTracking|targetFlag) == 0 {
 			sub.flags = flags | targetFlag | fNotified
 			if flags&(fEffect|fNotified) == fEffect {
-				if rs.queuedEffectsTail != nil {
-					rs.queuedEffectsTail.linked = &OneWayLink_signal{target: sub}
-					rs.queuedEffectsTail = rs.queuedEffectsTail.linked
-				} else {
-					rs.queuedEffects = &OneWayLink_signal{target: sub}
-					rs.queuedEffectsTail = rs.queuedEffects
-				}
+				rs.queueEffect(sub)
 			}
 		} else if flags&targetFlag == 0 &&
 			flags&fPropagated != 0 &&
@@ -327,8 +432,8 @@ top:
 
 		for branchDepth != 0 {
 			branchDepth--
-			current = branchs.target
-			branchs = branchs.linked
+			current = branchs[branchDepth]
+			branchs = branchs[:branchDepth]
 			if current != nil {
 				next = current.nextSub
 				if branchDepth != 0 {
@@ -341,6 +446,15 @@ top:
 		}
 		break
 	}
+	rs.propagateStack = branchs[:0]
+}
+
+// Appends an effect to the queue drained by processEffectNotifications.
+func (rs *ReactiveSystem) queueEffect(sub *signal) {
+	if rs.metrics != nil {
+		rs.metrics.queuedEffects.Add(1)
+	}
+	rs.queuedEffects = append(rs.queuedEffects, sub)
 }
 
 // Quickly propagates PendingComputed status to Dirty for each subscriber in the chain.
@@ -357,13 +471,7 @@ func (rs *ReactiveSystem) shallowPropagate(link *link) {
 		if justPendingDirty == fPendingComputed {
 			sub.flags = subFlags | fDirty | fNotified
 			if subFlags&(fEffect|fNotified) == fEffect {
-				if rs.queuedEffectsTail != nil {
-					rs.queuedEffectsTail.linked = &OneWayLink_signal{target: sub}
-					rs.queuedEffectsTail = rs.queuedEffectsTail.linked
-				} else {
-					rs.queuedEffects = &OneWayLink_signal{target: sub}
-					rs.queuedEffectsTail = rs.queuedEffects
-				}
+				rs.queueEffect(sub)
 			}
 		}
 		link = link.nextSub
@@ -385,6 +493,7 @@ func (rs *ReactiveSystem) startTracking(sub *signal) {
 	flags := sub.flags
 	revised := flags & ^(fNotified|fRecursed|fPropagated) | fTracking
 	sub.flags = revised
+	rs.debugTrack(sub)
 }
 
 // Concludes tracking of dependencies for the specified subscriber.
@@ -394,6 +503,7 @@ func (rs *ReactiveSystem) startTracking(sub *signal) {
 //
 // @param sub - The subscriber whose tracking is ending.
 func (rs *ReactiveSystem) endTracking(sub *signal) {
+	rs.debugHold()
 	depsTail := sub.depsTail
 	if depsTail != nil {
 		nextDep := depsTail.nextDep
@@ -409,6 +519,8 @@ func (rs *ReactiveSystem) endTracking(sub *signal) {
 		sub.deps = nil
 	}
 	sub.flags = sub.flags & ^fTracking
+	rs.debugUntrack(sub)
+	rs.debugRelease()
 }
 
 // Clears dependency-subscription relationships starting at the given link.
@@ -436,10 +548,35 @@ func (rs *ReactiveSystem) clearTracking(link *link) {
 			dep.subs = nextSub
 		}
 
+		if rs.tracer != nil {
+			rs.tracer.Unlink(dep.public(), link.sub.public())
+		}
+
+		// Released links are cleared even when they aren't pooled, so a walk
+		// that was holding one can tell it is gone.
+		link.dep = nil
+		link.sub = nil
+		link.prevSub = nil
+		link.nextSub = nil
+		link.nextDep = nil
+		if rs.linkPoolSize < maxPooledLinks {
+			link.nextDep = rs.linkPool
+			rs.linkPool = link
+			rs.linkPoolSize++
+		}
+
+		rs.debugCheck(dep)
+
 		subs := dep.subs
 		flags := dep.flags
 		if subs == nil && flags != 0 {
-			if flags&fDirty == 0 {
+			if flags&fEffect != 0 {
+				// An effect dropped by its owner is disposed. It may still
+				// be reached by the flush that is running, which must not
+				// run it again.
+				dep.flags = flags &^ fPropagated
+				rs.cleanupEffect(dep.ref.(*EffectRunner))
+			} else if flags&fDirty == 0 {
 				dep.flags = flags | fDirty
 			}
 
@@ -0,0 +1,16 @@
+package alien
+
+// Readable is anything with a reactive Value, such as *WriteableSignal and
+// *ReadonlySignal. Helpers built on top of the core primitives accept it so
+// they can be chained.
+type Readable[T any] interface {
+	Value() T
+}
+
+// ReadableFunc adapts a plain function to Readable. Signals read inside the
+// function are tracked like any other read.
+type ReadableFunc[T any] func() T
+
+func (f ReadableFunc[T]) Value() T {
+	return f()
+}
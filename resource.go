@@ -0,0 +1,115 @@
+package alien
+
+import "context"
+
+// ResourceSignal holds the result of an asynchronous fetch keyed by a
+// reactive source. Value, Loading and Error are reactive reads.
+type ResourceSignal[K comparable, V any] struct {
+	rs      *ReactiveSystem
+	version *WriteableSignal[uint64]
+	loading *WriteableSignal[bool]
+	value   V
+	err     error
+	// generation identifies the latest fetch; results of older fetches are
+	// dropped even if they arrive after being cancelled.
+	generation uint64
+	fetching   bool
+	suspense   *SuspenseBoundary
+	stop       ErrFn
+}
+
+// Resource fetches a value for every key produced by source. Each fetch runs
+// on its own goroutine with a context that is cancelled as soon as the key
+// changes or the owner of the resource is stopped. Results are handed back
+// through Dispatch, so they only become visible once the owning goroutine
+// calls Drain.
+//
+// While a fetch is in flight, Value and Error keep returning the previous
+// result and Loading reports true. A resource created inside Suspense
+// keeps that boundary pending until its fetch completes or it is stopped.
+// A resource created outside any owner runs until Stop is called.
+func Resource[K comparable, V any](rs *ReactiveSystem, source Readable[K], fetch func(ctx context.Context, key K) (V, error), opts ...Option) *ResourceSignal[K, V] {
+	r := &ResourceSignal[K, V]{
+		rs:      rs,
+		version: Signal(rs, uint64(0)),
+		loading: Signal(rs, false),
+	}
+	r.suspense, _ = Inject[*SuspenseBoundary](rs, suspenseKey{})
+	r.stop = Effect(rs, func() error {
+		key := source.Value()
+		r.start(key, fetch)
+		return nil
+	}, opts...)
+	return r
+}
+
+func (r *ResourceSignal[K, V]) start(key K, fetch func(ctx context.Context, key K) (V, error)) {
+	rs := r.rs
+	ctx, cancel := context.WithCancel(context.Background())
+	r.generation++
+	generation := r.generation
+	rs.onCleanup(func() {
+		cancel()
+		// A re-run starts the next fetch straight away; only a resource that
+		// was stopped mid-fetch is still on this generation once idle.
+		rs.whenIdle(func() {
+			if r.generation == generation && r.fetching {
+				r.setFetching(false)
+			}
+		})
+	})
+	r.setFetching(true)
+
+	go func() {
+		value, err := fetch(ctx, key)
+		rs.Dispatch(func() {
+			if generation != r.generation || ctx.Err() != nil {
+				return
+			}
+			r.value, r.err = value, err
+			rs.Batch(func() {
+				r.setFetching(false)
+				r.version.SetValue(r.version.value + 1)
+			})
+		})
+	}()
+}
+
+func (r *ResourceSignal[K, V]) setFetching(fetching bool) {
+	if r.fetching == fetching {
+		return
+	}
+	r.fetching = fetching
+	r.loading.SetValue(fetching)
+	if r.suspense == nil {
+		return
+	}
+	if fetching {
+		r.suspense.add(1)
+	} else {
+		r.suspense.add(-1)
+	}
+}
+
+// Stop cancels the fetch in flight and stops following source. Value and
+// Error keep the result of the latest completed fetch.
+func (r *ResourceSignal[K, V]) Stop() error {
+	return r.stop()
+}
+
+// Value returns the result of the latest completed fetch.
+func (r *ResourceSignal[K, V]) Value() V {
+	r.version.Value()
+	return r.value
+}
+
+// Error returns the error of the latest completed fetch.
+func (r *ResourceSignal[K, V]) Error() error {
+	r.version.Value()
+	return r.err
+}
+
+// Loading reports whether a fetch is in flight.
+func (r *ResourceSignal[K, V]) Loading() bool {
+	return r.loading.Value()
+}
@@ -0,0 +1,201 @@
+package alien_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFetcher answers fetches only when told to, so tests control exactly
+// when each result arrives.
+type fakeFetcher struct {
+	calls   chan fakeCall
+	mu      sync.Mutex
+	results map[int]chan fakeResult
+}
+
+type fakeCall struct {
+	key int
+	ctx context.Context
+}
+
+type fakeResult struct {
+	value string
+	err   error
+}
+
+func newFakeFetcher() *fakeFetcher {
+	return &fakeFetcher{
+		calls:   make(chan fakeCall, 16),
+		results: map[int]chan fakeResult{},
+	}
+}
+
+func (f *fakeFetcher) result(key int) chan fakeResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.results[key]; !ok {
+		f.results[key] = make(chan fakeResult, 1)
+	}
+	return f.results[key]
+}
+
+func (f *fakeFetcher) fetch(ctx context.Context, key int) (string, error) {
+	f.calls <- fakeCall{key: key, ctx: ctx}
+	select {
+	case r := <-f.result(key):
+		return r.value, r.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (f *fakeFetcher) nextCall(t *testing.T) fakeCall {
+	select {
+	case call := <-f.calls:
+		return call
+	case <-time.After(time.Second):
+		require.FailNow(t, "fetch was not called")
+		return fakeCall{}
+	}
+}
+
+func drainOnce(t *testing.T, rs *alien.ReactiveSystem) {
+	select {
+	case <-rs.Dispatched():
+		rs.Drain()
+	case <-time.After(time.Second):
+		require.FailNow(t, "nothing was dispatched")
+	}
+}
+
+func TestResourceLoadsAndUpdates(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	fetcher := newFakeFetcher()
+	// Results are queued up front, so each fetch completes immediately.
+	fetcher.result(1) <- fakeResult{value: "user 1"}
+	fetcher.result(2) <- fakeResult{err: errors.New("not found")}
+
+	userID := alien.Signal(rs, 1)
+	user := alien.Resource(rs, userID, fetcher.fetch)
+
+	var rendered []string
+	alien.Effect(rs, func() error {
+		rendered = append(rendered, fmt.Sprintf("loading=%v value=%q err=%v", user.Loading(), user.Value(), user.Error()))
+		return nil
+	})
+
+	fetcher.nextCall(t)
+	drainOnce(t, rs)
+	userID.SetValue(2)
+	fetcher.nextCall(t)
+	drainOnce(t, rs)
+
+	assert.Equal(t, []string{
+		`loading=true value="" err=<nil>`,
+		`loading=false value="user 1" err=<nil>`,
+		`loading=true value="user 1" err=<nil>`,
+		`loading=false value="" err=not found`,
+	}, rendered)
+}
+
+func TestResourceCancelsStaleFetches(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	fetcher := newFakeFetcher()
+
+	userID := alien.Signal(rs, 1)
+	user := alien.Resource(rs, userID, fetcher.fetch)
+	first := fetcher.nextCall(t)
+
+	userID.SetValue(2)
+	second := fetcher.nextCall(t)
+	select {
+	case <-first.ctx.Done():
+	case <-time.After(time.Second):
+		require.FailNow(t, "stale fetch was not cancelled")
+	}
+	assert.NoError(t, second.ctx.Err())
+
+	fetcher.result(2) <- fakeResult{value: "user 2"}
+	// The cancelled fetch dispatches too, but its result is dropped.
+	for user.Loading() {
+		drainOnce(t, rs)
+	}
+	assert.Equal(t, "user 2", user.Value())
+	assert.NoError(t, user.Error())
+}
+
+func TestResourceCancelledWithOwner(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	fetcher := newFakeFetcher()
+
+	userID := alien.Signal(rs, 1)
+	stop := alien.EffectScope(rs, func() error {
+		alien.Resource(rs, userID, fetcher.fetch)
+		return nil
+	})
+	call := fetcher.nextCall(t)
+	stop()
+
+	select {
+	case <-call.ctx.Done():
+	case <-time.After(time.Second):
+		require.FailNow(t, "fetch was not cancelled on stop")
+	}
+	userID.SetValue(2)
+	select {
+	case call := <-fetcher.calls:
+		assert.Fail(t, "stopped resource fetched again", "key %d", call.key)
+	default:
+	}
+}
+
+func TestResourceStop(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	fetcher := newFakeFetcher()
+
+	userID := alien.Signal(rs, 1)
+	user := alien.Resource(rs, userID, fetcher.fetch)
+	fetcher.result(1) <- fakeResult{value: "ada"}
+	fetcher.nextCall(t)
+	drainOnce(t, rs)
+
+	userID.SetValue(2)
+	call := fetcher.nextCall(t)
+	assert.True(t, user.Loading())
+	require.NoError(t, user.Stop())
+
+	select {
+	case <-call.ctx.Done():
+	case <-time.After(time.Second):
+		require.FailNow(t, "fetch was not cancelled on stop")
+	}
+	assert.False(t, user.Loading())
+	assert.Equal(t, "ada", user.Value())
+
+	userID.SetValue(3)
+	select {
+	case call := <-fetcher.calls:
+		assert.Fail(t, "stopped resource fetched again", "key %d", call.key)
+	default:
+	}
+	// The cancelled fetch still hands its result back, which is dropped.
+	drainOnce(t, rs)
+	assert.Equal(t, "ada", user.Value())
+	assert.NoError(t, user.Error())
+}
@@ -0,0 +1,86 @@
+package alien
+
+// SelectorSignal tracks which keys match the current value of a source.
+// Each key has its own boolean state, so a change of selection only
+// notifies readers of the keys whose state flipped.
+type SelectorSignal[K comparable] struct {
+	rs      *ReactiveSystem
+	equals  func(key, selected K) bool
+	keys    map[K]*WriteableSignal[bool]
+	current K
+	stop    ErrFn
+}
+
+// Selector builds a SelectorSignal over source. A key is selected when
+// equals(key, selected) holds; with a nil equals, keys are compared with ==
+// and a change only has to touch the previous and the new key instead of
+// every key that was ever read. The selector follows source until Stop is
+// called or its owner is stopped.
+func Selector[K comparable](rs *ReactiveSystem, source Readable[K], equals func(key, selected K) bool, opts ...Option) *SelectorSignal[K] {
+	s := &SelectorSignal[K]{
+		rs:     rs,
+		equals: equals,
+		keys:   map[K]*WriteableSignal[bool]{},
+	}
+	s.stop = Effect(rs, func() error {
+		selected := source.Value()
+		prev := s.current
+		s.current = selected
+		rs.Batch(func() {
+			s.update(prev, selected)
+		})
+		return nil
+	}, opts...)
+	return s
+}
+
+// Stop stops following source. Keys keep the state they had.
+func (s *SelectorSignal[K]) Stop() error {
+	return s.stop()
+}
+
+func (s *SelectorSignal[K]) update(prev, selected K) {
+	if s.equals == nil {
+		if prev == selected {
+			return
+		}
+		s.set(prev, false)
+		s.set(selected, true)
+		return
+	}
+	for key := range s.keys {
+		s.set(key, s.equals(key, selected))
+	}
+}
+
+// Keys nobody reads anymore are dropped the next time they are touched, so
+// the map only holds keys that are actually rendered.
+func (s *SelectorSignal[K]) set(key K, selected bool) {
+	state, ok := s.keys[key]
+	if !ok {
+		return
+	}
+	if state.subs == nil {
+		delete(s.keys, key)
+		return
+	}
+	state.SetValue(selected)
+}
+
+func (s *SelectorSignal[K]) isSelected(key K) bool {
+	if s.equals == nil {
+		return key == s.current
+	}
+	return s.equals(key, s.current)
+}
+
+// IsSelected reports whether key matches the selection. Reading it only
+// subscribes to the state of key.
+func (s *SelectorSignal[K]) IsSelected(key K) bool {
+	state, ok := s.keys[key]
+	if !ok {
+		state = Signal(s.rs, s.isSelected(key))
+		s.keys[key] = state
+	}
+	return state.Value()
+}
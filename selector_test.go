@@ -0,0 +1,97 @@
+package alien_test
+
+import (
+	"strings"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectorOnlyRerunsChangedRows(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	selected := alien.Signal(rs, 3)
+	isSelected := alien.Selector(rs, selected, nil)
+
+	const rows = 10_000
+	runs := make([]int, rows)
+	highlighted := make([]bool, rows)
+	for i := range rows {
+		alien.Effect(rs, func() error {
+			runs[i]++
+			highlighted[i] = isSelected.IsSelected(i)
+			return nil
+		})
+	}
+	assert.True(t, highlighted[3])
+
+	selected.SetValue(7)
+	total := 0
+	for _, n := range runs {
+		total += n
+	}
+	assert.Equal(t, rows+2, total, "only the old and new rows re-run")
+	assert.False(t, highlighted[3])
+	assert.True(t, highlighted[7])
+	assert.Equal(t, 2, runs[3])
+	assert.Equal(t, 2, runs[7])
+}
+
+func TestSelectorCustomEquals(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	prefix := alien.Signal(rs, "a")
+	matches := alien.Selector(rs, prefix, strings.HasPrefix)
+
+	names := []string{"ada", "alan", "grace"}
+	runs := map[string]int{}
+	matched := map[string]bool{}
+	for _, name := range names {
+		alien.Effect(rs, func() error {
+			runs[name]++
+			matched[name] = matches.IsSelected(name)
+			return nil
+		})
+	}
+	assert.Equal(t, map[string]bool{"ada": true, "alan": true, "grace": false}, matched)
+
+	prefix.SetValue("al")
+	assert.Equal(t, map[string]bool{"ada": false, "alan": true, "grace": false}, matched)
+	assert.Equal(t, map[string]int{"ada": 2, "alan": 1, "grace": 1}, runs)
+}
+
+func TestSelectorUntrackedRead(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	selected := alien.Signal(rs, "a")
+	isSelected := alien.Selector(rs, selected, nil)
+
+	assert.True(t, isSelected.IsSelected("a"))
+	selected.SetValue("b")
+	assert.False(t, isSelected.IsSelected("a"), "an unsubscribed key is not left stale")
+	assert.True(t, isSelected.IsSelected("b"))
+}
+
+func TestSelectorStop(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	selected := alien.Signal(rs, 1)
+	isSelected := alien.Selector(rs, selected, nil)
+
+	var seen []bool
+	alien.Effect(rs, func() error {
+		seen = append(seen, isSelected.IsSelected(1))
+		return nil
+	})
+	assert.NoError(t, isSelected.Stop())
+
+	selected.SetValue(2)
+	assert.Equal(t, []bool{true}, seen)
+	assert.True(t, isSelected.IsSelected(1))
+	assert.False(t, isSelected.IsSelected(2))
+}
@@ -11,6 +11,8 @@ func (s *WriteableSignal[T]) isSignalAware() {}
 func (s *WriteableSignal[T]) Value() T {
 	if s.rs.activeSub != nil {
 		s.rs.link(&s.signal, s.rs.activeSub)
+	} else if s.rs.parallel {
+		s.rs.parallelRead(&s.signal)
 	}
 	return s.value
 }
@@ -19,7 +21,17 @@ func (s *WriteableSignal[T]) SetValue(v T) {
 	if s.value == v {
 		return
 	}
+	s.rs.sweepIfDropped()
+	if s.rs.causality {
+		s.rs.recordWrite(&s.signal, s.value, v)
+	}
 	s.value = v
+	if s.rs.metrics != nil {
+		s.rs.metrics.recordWrite(&s.signal)
+	}
+	if s.rs.tracer != nil {
+		s.rs.tracer.SignalWrite(s)
+	}
 	subs := s.signal.subs
 	if subs != nil {
 		s.rs.propagate(subs)
@@ -29,7 +41,7 @@ func (s *WriteableSignal[T]) SetValue(v T) {
 	}
 }
 
-func Signal[T comparable](rs *ReactiveSystem, initialValue T) *WriteableSignal[T] {
+func Signal[T comparable](rs *ReactiveSystem, initialValue T, opts ...Option) *WriteableSignal[T] {
 	s := &WriteableSignal[T]{
 		rs:     rs,
 		value:  initialValue,
@@ -37,5 +49,6 @@ func Signal[T comparable](rs *ReactiveSystem, initialValue T) *WriteableSignal[T
 	}
 	signal := &s.signal
 	signal.ref = s
+	rs.initNode(signal, opts)
 	return s
 }
@@ -0,0 +1,91 @@
+package alien
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlogLevels selects the level each kind of event is logged at.
+type SlogLevels struct {
+	Write       slog.Level
+	Batch       slog.Level
+	Recompute   slog.Level
+	Effect      slog.Level
+	EffectError slog.Level
+	Link        slog.Level
+}
+
+// DefaultSlogLevels logs effect errors at Error and everything else at Debug.
+func DefaultSlogLevels() SlogLevels {
+	return SlogLevels{
+		Write:       slog.LevelDebug,
+		Batch:       slog.LevelDebug,
+		Recompute:   slog.LevelDebug,
+		Effect:      slog.LevelDebug,
+		EffectError: slog.LevelError,
+		Link:        slog.LevelDebug,
+	}
+}
+
+// SlogTracer is a Tracer that logs every event through a slog.Logger.
+type SlogTracer struct {
+	logger *slog.Logger
+	levels SlogLevels
+}
+
+func NewSlogTracer(logger *slog.Logger, levels SlogLevels) *SlogTracer {
+	return &SlogTracer{logger: logger, levels: levels}
+}
+
+func (t *SlogTracer) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	ctx := context.Background()
+	if !t.logger.Enabled(ctx, level) {
+		return
+	}
+	t.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func nodeAttr(key string, node SignalAware) slog.Attr {
+	return slog.String(key, node.String())
+}
+
+func (t *SlogTracer) SignalWrite(node SignalAware) {
+	t.log(t.levels.Write, "signal write", nodeAttr("node", node))
+}
+
+func (t *SlogTracer) BatchStart(depth int) {
+	t.log(t.levels.Batch, "batch start", slog.Int("depth", depth))
+}
+
+func (t *SlogTracer) BatchEnd(depth int, elapsed time.Duration) {
+	t.log(t.levels.Batch, "batch end", slog.Int("depth", depth), slog.Duration("duration", elapsed))
+}
+
+func (t *SlogTracer) ComputedRecompute(node SignalAware, elapsed time.Duration, changed bool) {
+	t.log(t.levels.Recompute, "computed recompute",
+		nodeAttr("node", node),
+		slog.Duration("duration", elapsed),
+		slog.Bool("changed", changed),
+	)
+}
+
+func (t *SlogTracer) EffectRun(node SignalAware, elapsed time.Duration, err error) {
+	if err != nil {
+		t.log(t.levels.EffectError, "effect error",
+			nodeAttr("node", node),
+			slog.Duration("duration", elapsed),
+			slog.Any("error", err),
+		)
+		return
+	}
+	t.log(t.levels.Effect, "effect run", nodeAttr("node", node), slog.Duration("duration", elapsed))
+}
+
+func (t *SlogTracer) Link(dep, sub SignalAware) {
+	t.log(t.levels.Link, "link", nodeAttr("dep", dep), nodeAttr("sub", sub))
+}
+
+func (t *SlogTracer) Unlink(dep, sub SignalAware) {
+	t.log(t.levels.Link, "unlink", nodeAttr("dep", dep), nodeAttr("sub", sub))
+}
@@ -0,0 +1,100 @@
+package alien_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var lines []map[string]any
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		line := map[string]any{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestSlogTracer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	tracer := alien.NewSlogTracer(logger, alien.DefaultSlogLevels())
+
+	errBoom := errors.New("boom")
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {}, alien.WithTracer(tracer))
+
+	count := alien.Signal(rs, 1, alien.WithName("count"))
+	double := alien.Computed(rs, func(oldValue int) int {
+		return count.Value() * 2
+	}, alien.WithName("double"))
+	alien.Effect(rs, func() error {
+		if double.Value() > 2 {
+			return errBoom
+		}
+		return nil
+	}, alien.WithName("printer"))
+
+	rs.Batch(func() {
+		count.SetValue(2)
+	})
+
+	var msgs []string
+	byMsg := map[string][]map[string]any{}
+	for _, line := range decodeLogLines(t, buf) {
+		msg := line["msg"].(string)
+		msgs = append(msgs, msg)
+		byMsg[msg] = append(byMsg[msg], line)
+	}
+
+	assert.Equal(t, []string{
+		"link",               // count -> double
+		"computed recompute", // double
+		"link",               // double -> printer
+		"effect run",         // printer, first run
+		"batch start",
+		"signal write",
+		"computed recompute",
+		"effect error",
+		"batch end",
+	}, msgs)
+
+	assert.Equal(t, `computed "double"`, byMsg["link"][1]["dep"])
+	assert.Equal(t, `effect "printer"`, byMsg["link"][1]["sub"])
+	assert.Equal(t, `signal "count"`, byMsg["signal write"][0]["node"])
+	assert.Equal(t, true, byMsg["computed recompute"][1]["changed"])
+	assert.Contains(t, byMsg["computed recompute"][1], "duration")
+	assert.Equal(t, "ERROR", byMsg["effect error"][0]["level"])
+	assert.Equal(t, "boom", byMsg["effect error"][0]["error"])
+	assert.Equal(t, float64(1), byMsg["batch end"][0]["depth"])
+}
+
+func TestSlogTracerLevels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	levels := alien.DefaultSlogLevels()
+	levels.Write = slog.LevelInfo
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithTracer(alien.NewSlogTracer(logger, levels)))
+
+	a := alien.Signal(rs, 0)
+	alien.Effect(rs, func() error {
+		a.Value()
+		return nil
+	})
+	a.SetValue(1)
+
+	lines := decodeLogLines(t, buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "signal write", lines[0]["msg"])
+	assert.Equal(t, "signal", lines[0]["node"])
+}
@@ -0,0 +1,77 @@
+package alien
+
+import "context"
+
+type suspenseKey struct{}
+
+// SuspenseBoundary counts the resources created inside it that are still
+// fetching. Boundaries nest: a pending inner boundary keeps its outer
+// boundary pending too.
+type SuspenseBoundary struct {
+	rs      *ReactiveSystem
+	pending *WriteableSignal[int]
+	// isPending only changes when pending crosses zero, so readers of
+	// Pending are not rerun for every resource that settles.
+	isPending *ReadonlySignal[bool]
+	parent    *SuspenseBoundary
+	stop      ErrFn
+}
+
+// Suspense runs fn in a new effect scope whose resources are tracked by the
+// returned boundary. Stopping the boundary stops the scope and releases
+// every fetch still pending inside it.
+func Suspense(rs *ReactiveSystem, fn ErrFn, opts ...Option) *SuspenseBoundary {
+	s := &SuspenseBoundary{
+		rs:      rs,
+		pending: Signal(rs, 0),
+	}
+	s.isPending = Computed(rs, func(oldValue bool) bool {
+		return s.pending.Value() > 0
+	})
+	s.parent, _ = Inject[*SuspenseBoundary](rs, suspenseKey{})
+	s.stop = EffectScope(rs, func() error {
+		Provide(rs, suspenseKey{}, s)
+		return fn()
+	}, opts...)
+	return s
+}
+
+// Pending reports whether any resource inside the boundary is fetching.
+func (s *SuspenseBoundary) Pending() bool {
+	return s.isPending.Value()
+}
+
+// Stop stops the scope created by Suspense.
+func (s *SuspenseBoundary) Stop() error {
+	return s.stop()
+}
+
+// WaitReady drains dispatched work until nothing inside the boundary is
+// pending or ctx is done. It must be called from the goroutine that owns
+// the system.
+func (s *SuspenseBoundary) WaitReady(ctx context.Context) error {
+	for s.pending.value > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.rs.Dispatched():
+			s.rs.Drain()
+		}
+	}
+	return nil
+}
+
+func (s *SuspenseBoundary) add(delta int) {
+	before := s.pending.value
+	after := before + delta
+	s.pending.SetValue(after)
+	if s.parent == nil {
+		return
+	}
+	switch {
+	case before == 0 && after > 0:
+		s.parent.add(1)
+	case before > 0 && after == 0:
+		s.parent.add(-1)
+	}
+}
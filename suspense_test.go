@@ -0,0 +1,112 @@
+package alien_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuspenseWaitsForResources(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	fetcher := newFakeFetcher()
+
+	var user, team *alien.ResourceSignal[int, string]
+	page := alien.Suspense(rs, func() error {
+		user = alien.Resource(rs, alien.Signal(rs, 1), fetcher.fetch)
+		team = alien.Resource(rs, alien.Signal(rs, 2), fetcher.fetch)
+		return nil
+	})
+
+	var pending []bool
+	alien.Effect(rs, func() error {
+		pending = append(pending, page.Pending())
+		return nil
+	})
+	assert.True(t, page.Pending())
+
+	fetcher.nextCall(t)
+	fetcher.nextCall(t)
+	fetcher.result(1) <- fakeResult{value: "ada"}
+	fetcher.result(2) <- fakeResult{value: "compilers"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, page.WaitReady(ctx))
+	assert.False(t, page.Pending())
+	assert.Equal(t, "ada", user.Value())
+	assert.Equal(t, "compilers", team.Value())
+	assert.Equal(t, []bool{true, false}, pending)
+}
+
+func TestSuspenseNested(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	fetcher := newFakeFetcher()
+
+	var inner *alien.SuspenseBoundary
+	outer := alien.Suspense(rs, func() error {
+		inner = alien.Suspense(rs, func() error {
+			alien.Resource(rs, alien.Signal(rs, 1), fetcher.fetch)
+			return nil
+		})
+		return nil
+	})
+	assert.True(t, inner.Pending())
+	assert.True(t, outer.Pending(), "a pending inner boundary keeps the outer one pending")
+
+	fetcher.nextCall(t)
+	fetcher.result(1) <- fakeResult{value: "done"}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, outer.WaitReady(ctx))
+	assert.False(t, inner.Pending())
+}
+
+func TestSuspenseWaitReadyTimesOut(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	fetcher := newFakeFetcher()
+
+	page := alien.Suspense(rs, func() error {
+		alien.Resource(rs, alien.Signal(rs, 1), fetcher.fetch)
+		return nil
+	})
+	fetcher.nextCall(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, page.WaitReady(ctx), context.DeadlineExceeded)
+	assert.True(t, page.Pending())
+	page.Stop()
+}
+
+func TestSuspenseStopReleasesPending(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	fetcher := newFakeFetcher()
+
+	var stopInner func() error
+	page := alien.Suspense(rs, func() error {
+		stopInner = alien.EffectScope(rs, func() error {
+			alien.Resource(rs, alien.Signal(rs, 1), fetcher.fetch)
+			return nil
+		})
+		return nil
+	})
+	call := fetcher.nextCall(t)
+	assert.True(t, page.Pending())
+
+	stopInner()
+	<-call.ctx.Done()
+	assert.False(t, page.Pending())
+	require.NoError(t, page.WaitReady(context.Background()))
+}
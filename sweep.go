@@ -0,0 +1,67 @@
+package alien
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// WithWeakComputeds lets computeds be garbage collected once their handle is
+// unreachable, even though the signals they read still link to them. They
+// are unlinked once the collector noticed, the next time a signal is
+// written or a computed or effect is created. Registering
+// every handle with the runtime makes creating computeds considerably
+// slower, which is why this is opt-in.
+func WithWeakComputeds() SystemOption {
+	return func(rs *ReactiveSystem) {
+		rs.weakComputeds = true
+	}
+}
+
+// Computeds whose handle was garbage collected, reported from the runtime's
+// cleanup goroutine and unlinked by the goroutine owning the system.
+type droppedComputeds struct {
+	pending atomic.Bool
+	mu      sync.Mutex
+	nodes   []*signal
+}
+
+// Runs on the runtime's cleanup goroutine once the handle of a computed is
+// unreachable, so it only records the node.
+func (rs *ReactiveSystem) dropComputed(node *signal) {
+	d := &rs.dropped
+	d.mu.Lock()
+	d.nodes = append(d.nodes, node)
+	d.mu.Unlock()
+	d.pending.Store(true)
+}
+
+// Schedules sweepDropped if the collector reported anything. Writes and node
+// creation call it, as a graph that keeps changing goes through one of them
+// sooner or later, while reads are left alone to stay cheap.
+func (rs *ReactiveSystem) sweepIfDropped() {
+	if rs.dropped.pending.Load() {
+		rs.whenIdle(rs.sweepDropped)
+	}
+}
+
+// Unlinks dropped computeds from their dependencies, which are then the only
+// thing still referencing them. A computed that something else subscribes to
+// is left alone; it is unlinked by clearTracking once that subscriber drops
+// it.
+func (rs *ReactiveSystem) sweepDropped() {
+	d := &rs.dropped
+	d.pending.Store(false)
+	d.mu.Lock()
+	nodes := d.nodes
+	d.nodes = nil
+	d.mu.Unlock()
+
+	for _, node := range nodes {
+		if node.subs != nil || node.deps == nil {
+			continue
+		}
+		rs.startTracking(node)
+		rs.endTracking(node)
+		node.flags |= fDirty
+	}
+}
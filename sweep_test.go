@@ -0,0 +1,111 @@
+package alien_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func TestDroppedComputedsAreCollected(t *testing.T) {
+	if testing.Short() {
+		t.Skip("creates a million computeds")
+	}
+	skipWhenDebugging(t)
+
+	// Sweeps happen on writes and on node creation, so either one is enough
+	// to keep memory bounded.
+	for _, tc := range []struct {
+		name  string
+		nudge func(rs *alien.ReactiveSystem, src *alien.WriteableSignal[int])
+	}{
+		{"write", func(rs *alien.ReactiveSystem, src *alien.WriteableSignal[int]) {
+			src.SetValue(src.Value() + 1)
+		}},
+		{"create", func(rs *alien.ReactiveSystem, src *alien.WriteableSignal[int]) {
+			alien.Computed(rs, func(oldValue int) int { return 0 })
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+				assert.FailNow(t, err.Error())
+			}, alien.WithWeakComputeds())
+			src := alien.Signal(rs, 0)
+			before := heapInUse()
+
+			const n = 1_000_000
+			for i := range n {
+				c := alien.Computed(rs, func(oldValue int) int {
+					return src.Value() + 1
+				})
+				// Reading links the computed to src, which keeps it alive
+				// unless it is unlinked once the handle is gone.
+				c.Value()
+				if i%100_000 == 0 {
+					runtime.GC()
+					tc.nudge(rs, src)
+				}
+			}
+
+			// Cleanups run on their own goroutine after a collection, and are
+			// only swept by the next nudge.
+			const limit = 16 << 20
+			var grown uint64
+			for range 50 {
+				tc.nudge(rs, src)
+				if after := heapInUse(); after > before {
+					grown = after - before
+				} else {
+					grown = 0
+				}
+				if grown < limit {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			assert.Less(t, grown, uint64(limit), "%d dropped computeds still take %d bytes", n, grown)
+		})
+	}
+}
+
+func TestKeptComputedsStayLinked(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithWeakComputeds())
+	src := alien.Signal(rs, 1)
+
+	// The intermediate handle is only reachable through the effect's chain,
+	// so it is dropped, but the chain must keep working.
+	tail := func() *alien.ReadonlySignal[int] {
+		double := alien.Computed(rs, func(oldValue int) int {
+			return src.Value() * 2
+		})
+		return alien.Computed(rs, func(oldValue int) int {
+			return double.Value() + 1
+		})
+	}()
+	var seen []int
+	alien.Effect(rs, func() error {
+		seen = append(seen, alien.Computed(rs, func(oldValue int) int {
+			return tail.Value()
+		}).Value())
+		return nil
+	})
+
+	for i := 2; i <= 4; i++ {
+		runtime.GC()
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+		src.SetValue(i)
+	}
+	assert.Equal(t, []int{3, 5, 7, 9}, seen)
+}
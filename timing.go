@@ -0,0 +1,237 @@
+package alien
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Debounce follows src, but only once src has stopped changing for d. Every
+// change restarts the wait. The settled value is applied through Dispatch,
+// so it becomes visible the next time the owner of rs calls Drain. Calling
+// stop, or stopping the owner, drops the pending value and stops following
+// src.
+func Debounce[T comparable](rs *ReactiveSystem, src Readable[T], d time.Duration, opts ...Option) (debounced *ReadonlySignal[T], stop ErrFn) {
+	out := Signal(rs, *new(T))
+	first := true
+	stop = Effect(rs, func() error {
+		v := src.Value()
+		if first {
+			first = false
+			out.SetValue(v)
+			return nil
+		}
+
+		live := true
+		timer := rs.clock.AfterFunc(d, func() {
+			rs.Dispatch(func() {
+				if live {
+					out.SetValue(v)
+				}
+			})
+		})
+		// Runs when src changes again or the owner is stopped, either way
+		// this value is no longer the one to deliver.
+		rs.onCleanup(func() {
+			live = false
+			timer.Stop()
+		})
+		return nil
+	})
+	return Computed(rs, func(oldValue T) T {
+		return out.Value()
+	}, opts...), stop
+}
+
+// ThrottleEdge selects when Throttle delivers values within a window.
+type ThrottleEdge uint8
+
+const (
+	// ThrottleLeading delivers the change that opens a window right away.
+	ThrottleLeading ThrottleEdge = 1 << iota
+	// ThrottleTrailing delivers the latest change made during a window when
+	// the window closes.
+	ThrottleTrailing
+)
+
+// Throttle follows src, but delivers at most one value per window of d. A
+// window opens with the first change after a quiet period; edges picks which
+// changes are delivered and must contain at least one edge, as nothing would
+// be delivered after the first value otherwise. Values delivered when a
+// window closes go through Dispatch. Calling stop, or stopping the owner,
+// closes the window without delivering anything.
+func Throttle[T comparable](rs *ReactiveSystem, src Readable[T], d time.Duration, edges ThrottleEdge, opts ...Option) (throttled *ReadonlySignal[T], stop ErrFn) {
+	if edges&(ThrottleLeading|ThrottleTrailing) == 0 {
+		panic(fmt.Sprintf("alien: Throttle edges %d select neither ThrottleLeading nor ThrottleTrailing", edges))
+	}
+	out := Signal(rs, *new(T))
+	var (
+		first   = true
+		open    bool
+		pending bool
+		latest  T
+		timer   Timer
+		stopped bool
+	)
+
+	var openWindow func()
+	closeWindow := func() {
+		if stopped {
+			return
+		}
+		open = false
+		if pending && edges&ThrottleTrailing != 0 {
+			pending = false
+			out.SetValue(latest)
+			openWindow()
+			return
+		}
+		pending = false
+	}
+	openWindow = func() {
+		open = true
+		timer = rs.clock.AfterFunc(d, func() {
+			rs.Dispatch(closeWindow)
+		})
+	}
+
+	// The window outlives individual runs of the effect, so it is torn down
+	// by a scope that is only stopped with its owner or by stop.
+	stop = EffectScope(rs, func() error {
+		rs.onCleanup(func() {
+			stopped = true
+			if timer != nil {
+				timer.Stop()
+			}
+		})
+		Effect(rs, func() error {
+			v := src.Value()
+			switch {
+			case first:
+				first = false
+				out.SetValue(v)
+			case open:
+				pending, latest = true, v
+			default:
+				if edges&ThrottleLeading != 0 {
+					out.SetValue(v)
+				} else {
+					pending, latest = true, v
+				}
+				openWindow()
+			}
+			return nil
+		})
+		return nil
+	})
+	return Computed(rs, func(oldValue T) T {
+		return out.Value()
+	}, opts...), stop
+}
+
+// Interval counts how many times d has elapsed since it was created. Ticks
+// are applied through Dispatch, until stop is called or the owner is
+// stopped. It panics if d is not positive, as it would tick without end.
+func Interval(rs *ReactiveSystem, d time.Duration, opts ...Option) (ticks *ReadonlySignal[int], stop ErrFn) {
+	if d <= 0 {
+		panic(fmt.Sprintf("alien: Interval(%v) needs a positive period", d))
+	}
+	count := Signal(rs, 0)
+	stop = rs.repeat(func(now time.Time) time.Duration {
+		return d
+	}, func(at time.Time) {
+		count.SetValue(count.value + 1)
+	})
+	return Computed(rs, func(oldValue int) int {
+		return count.Value()
+	}, opts...), stop
+}
+
+// Timeout flips to true once d has elapsed. The flip is applied through
+// Dispatch and never happens if stop is called or the owner is stopped
+// first.
+func Timeout(rs *ReactiveSystem, d time.Duration, opts ...Option) (expired *ReadonlySignal[bool], stop ErrFn) {
+	done := Signal(rs, false)
+	stop = EffectScope(rs, func() error {
+		live := true
+		timer := rs.clock.AfterFunc(d, func() {
+			rs.Dispatch(func() {
+				if live {
+					done.SetValue(true)
+				}
+			})
+		})
+		rs.onCleanup(func() {
+			live = false
+			timer.Stop()
+		})
+		return nil
+	})
+	return Computed(rs, func(oldValue bool) bool {
+		return done.Value()
+	}, opts...), stop
+}
+
+// Now holds the current time truncated to resolution, updating on every
+// multiple of resolution. Updates are applied through Dispatch, until stop
+// is called or the owner is stopped. It panics if resolution is not
+// positive, as it would update without end.
+func Now(rs *ReactiveSystem, resolution time.Duration, opts ...Option) (now *ReadonlySignal[time.Time], stop ErrFn) {
+	if resolution <= 0 {
+		panic(fmt.Sprintf("alien: Now(%v) needs a positive resolution", resolution))
+	}
+	current := Signal(rs, rs.clock.Now().Truncate(resolution))
+	stop = rs.repeat(func(now time.Time) time.Duration {
+		return now.Truncate(resolution).Add(resolution).Sub(now)
+	}, func(at time.Time) {
+		current.SetValue(at.Truncate(resolution))
+	})
+	return Computed(rs, func(oldValue time.Time) time.Time {
+		return current.Value()
+	}, opts...), stop
+}
+
+// Calls tick through Dispatch every time the delay returned by next has
+// elapsed, until the returned function or the current owner stops it. The
+// next timer is scheduled as soon as the previous one fires, so ticks don't
+// drift while they wait to be drained. next must return positive delays.
+func (rs *ReactiveSystem) repeat(next func(now time.Time) time.Duration, tick func(at time.Time)) ErrFn {
+	var (
+		mu      sync.Mutex
+		timer   Timer
+		stopped bool
+	)
+	var fire func()
+	fire = func() {
+		mu.Lock()
+		if stopped {
+			mu.Unlock()
+			return
+		}
+		at := rs.clock.Now()
+		timer = rs.clock.AfterFunc(next(at), fire)
+		mu.Unlock()
+
+		rs.Dispatch(func() {
+			mu.Lock()
+			live := !stopped
+			mu.Unlock()
+			if live {
+				tick(at)
+			}
+		})
+	}
+
+	return EffectScope(rs, func() error {
+		mu.Lock()
+		timer = rs.clock.AfterFunc(next(rs.clock.Now()), fire)
+		mu.Unlock()
+		rs.onCleanup(func() {
+			mu.Lock()
+			defer mu.Unlock()
+			stopped = true
+			timer.Stop()
+		})
+		return nil
+	})
+}
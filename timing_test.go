@@ -0,0 +1,279 @@
+package alien_test
+
+import (
+	"testing"
+	"time"
+
+	alien "github.com/delaneyj/alien-signals-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// step advances the clock and applies whatever the timers dispatched.
+func step(rs *alien.ReactiveSystem, clock *alien.FakeClock, d time.Duration) {
+	clock.Advance(d)
+	rs.Drain()
+}
+
+func TestDebounce(t *testing.T) {
+	clock := alien.NewFakeClock(time.Time{})
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithClock(clock))
+	query := alien.Signal(rs, "")
+	debounced, _ := alien.Debounce(rs, query, 100*time.Millisecond)
+
+	var searches []string
+	alien.Effect(rs, func() error {
+		searches = append(searches, debounced.Value())
+		return nil
+	})
+
+	query.SetValue("a")
+	step(rs, clock, 60*time.Millisecond)
+	query.SetValue("al")
+	step(rs, clock, 60*time.Millisecond)
+	query.SetValue("ali")
+	step(rs, clock, 99*time.Millisecond)
+	assert.Equal(t, []string{""}, searches, "still typing")
+
+	step(rs, clock, time.Millisecond)
+	assert.Equal(t, []string{"", "ali"}, searches)
+}
+
+func TestDebounceStopped(t *testing.T) {
+	clock := alien.NewFakeClock(time.Time{})
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithClock(clock))
+	query := alien.Signal(rs, "")
+
+	var debounced *alien.ReadonlySignal[string]
+	stop := alien.EffectScope(rs, func() error {
+		debounced, _ = alien.Debounce(rs, query, 100*time.Millisecond)
+		return nil
+	})
+	query.SetValue("a")
+	// The timer fires but its result is still waiting to be drained.
+	clock.Advance(100 * time.Millisecond)
+	stop()
+	rs.Drain()
+	assert.Equal(t, "", debounced.Value())
+}
+
+func TestDebounceStop(t *testing.T) {
+	clock := alien.NewFakeClock(time.Time{})
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithClock(clock))
+	query := alien.Signal(rs, "")
+	debounced, stop := alien.Debounce(rs, query, 100*time.Millisecond)
+
+	query.SetValue("a")
+	step(rs, clock, 50*time.Millisecond)
+	assert.NoError(t, stop())
+	assert.Zero(t, clock.Pending())
+
+	query.SetValue("ab")
+	step(rs, clock, time.Second)
+	assert.Equal(t, "", debounced.Value())
+	assert.Zero(t, clock.Pending())
+}
+
+func TestThrottle(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		edges alien.ThrottleEdge
+		want  []int
+	}{
+		{"leading", alien.ThrottleLeading, []int{0, 1, 5}},
+		{"trailing", alien.ThrottleTrailing, []int{0, 3, 5}},
+		{"both", alien.ThrottleLeading | alien.ThrottleTrailing, []int{0, 1, 3, 5}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := alien.NewFakeClock(time.Time{})
+			rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+				assert.FailNow(t, err.Error())
+			}, alien.WithClock(clock))
+			count := alien.Signal(rs, 0)
+			throttled, _ := alien.Throttle(rs, count, 100*time.Millisecond, tc.edges)
+
+			var seen []int
+			alien.Effect(rs, func() error {
+				seen = append(seen, throttled.Value())
+				return nil
+			})
+
+			// A burst within one window.
+			for i := 1; i <= 3; i++ {
+				count.SetValue(i)
+				step(rs, clock, 10*time.Millisecond)
+			}
+			step(rs, clock, 200*time.Millisecond)
+
+			// Quiet long enough for every window to close, then one more
+			// burst.
+			step(rs, clock, time.Second)
+			count.SetValue(5)
+			step(rs, clock, time.Second)
+			assert.Equal(t, tc.want, seen)
+		})
+	}
+}
+
+func TestThrottleStop(t *testing.T) {
+	clock := alien.NewFakeClock(time.Time{})
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithClock(clock))
+	count := alien.Signal(rs, 0)
+	throttled, stop := alien.Throttle(rs, count, 100*time.Millisecond, alien.ThrottleLeading|alien.ThrottleTrailing)
+
+	count.SetValue(1)
+	count.SetValue(2)
+	assert.Equal(t, 1, throttled.Value())
+	// The window closes with 2 waiting to be delivered.
+	clock.Advance(100 * time.Millisecond)
+	assert.NoError(t, stop())
+	rs.Drain()
+
+	count.SetValue(3)
+	step(rs, clock, time.Second)
+	assert.Equal(t, 1, throttled.Value())
+	assert.Zero(t, clock.Pending())
+}
+
+func TestThrottleNeedsAnEdge(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithClock(alien.NewFakeClock(time.Time{})))
+	count := alien.Signal(rs, 0)
+	assert.PanicsWithValue(t, "alien: Throttle edges 0 select neither ThrottleLeading nor ThrottleTrailing", func() {
+		alien.Throttle(rs, count, 100*time.Millisecond, 0)
+	})
+	assert.Panics(t, func() {
+		alien.Throttle(rs, count, 100*time.Millisecond, 4)
+	})
+}
+
+func TestFakeClockFiresInOrder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := alien.NewFakeClock(start)
+
+	var fired []time.Duration
+	record := func() {
+		fired = append(fired, clock.Now().Sub(start))
+	}
+	clock.AfterFunc(30*time.Millisecond, record)
+	clock.AfterFunc(10*time.Millisecond, func() {
+		record()
+		clock.AfterFunc(10*time.Millisecond, record)
+	})
+	stopped := clock.AfterFunc(15*time.Millisecond, record)
+	assert.True(t, stopped.Stop())
+	assert.False(t, stopped.Stop())
+
+	clock.Advance(25 * time.Millisecond)
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, fired)
+	assert.Equal(t, 25*time.Millisecond, clock.Now().Sub(start))
+	assert.Equal(t, 1, clock.Pending())
+}
+
+func TestInterval(t *testing.T) {
+	clock := alien.NewFakeClock(time.Time{})
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithClock(clock))
+
+	var ticks *alien.ReadonlySignal[int]
+	stop := alien.EffectScope(rs, func() error {
+		ticks, _ = alien.Interval(rs, time.Second)
+		return nil
+	})
+	var seen []int
+	alien.Effect(rs, func() error {
+		seen = append(seen, ticks.Value())
+		return nil
+	})
+
+	step(rs, clock, 999*time.Millisecond)
+	step(rs, clock, time.Millisecond)
+	// Ticks that pile up before a drain are all applied in one batch.
+	step(rs, clock, 3*time.Second)
+	assert.Equal(t, []int{0, 1, 4}, seen)
+
+	stop()
+	step(rs, clock, time.Minute)
+	assert.Equal(t, 4, ticks.Value())
+	assert.Zero(t, clock.Pending())
+}
+
+func TestTimeout(t *testing.T) {
+	clock := alien.NewFakeClock(time.Time{})
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithClock(clock))
+
+	expired, _ := alien.Timeout(rs, time.Second)
+	var abandoned *alien.ReadonlySignal[bool]
+	stopScope := alien.EffectScope(rs, func() error {
+		abandoned, _ = alien.Timeout(rs, time.Second)
+		return nil
+	})
+	cancelled, stop := alien.Timeout(rs, time.Second)
+
+	step(rs, clock, 500*time.Millisecond)
+	assert.False(t, expired.Value())
+	stopScope()
+	assert.NoError(t, stop())
+	step(rs, clock, 500*time.Millisecond)
+	assert.True(t, expired.Value())
+	assert.False(t, abandoned.Value())
+	assert.False(t, cancelled.Value())
+	assert.Zero(t, clock.Pending())
+}
+
+func TestNow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 400_000_000, time.UTC)
+	clock := alien.NewFakeClock(start)
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithClock(clock))
+
+	now, stop := alien.Now(rs, time.Second)
+	var seen []string
+	alien.Effect(rs, func() error {
+		seen = append(seen, now.Value().Format(time.TimeOnly))
+		return nil
+	})
+
+	step(rs, clock, 500*time.Millisecond)
+	step(rs, clock, 100*time.Millisecond)
+	step(rs, clock, time.Second)
+	// Only the latest time survives when several ticks are drained at once.
+	step(rs, clock, 2*time.Second)
+	assert.Equal(t, []string{"12:00:00", "12:00:01", "12:00:02", "12:00:04"}, seen)
+
+	// A tick already waiting to be drained is dropped too.
+	clock.Advance(time.Second)
+	assert.NoError(t, stop())
+	step(rs, clock, time.Minute)
+	assert.Len(t, seen, 4)
+	assert.Zero(t, clock.Pending())
+}
+
+func TestRepeatingTimersNeedAPositivePeriod(t *testing.T) {
+	clock := alien.NewFakeClock(time.Time{})
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	}, alien.WithClock(clock))
+
+	assert.PanicsWithValue(t, "alien: Interval(0s) needs a positive period", func() {
+		alien.Interval(rs, 0)
+	})
+	assert.PanicsWithValue(t, "alien: Now(-1s) needs a positive resolution", func() {
+		alien.Now(rs, -time.Second)
+	})
+	// Nothing was scheduled, so advancing the clock returns.
+	clock.Advance(time.Minute)
+	assert.Zero(t, clock.Pending())
+}
@@ -445,3 +445,83 @@ func TestShouldKeepGraphConsistentOnComputedErrors(t *testing.T) {
 	a.SetValue(1)
 	assert.Equal(t, 1, c.Value())
 }
+
+// A computed whose dependency is shared with other subscribers is checked
+// while the first link in its subscriber chain is the one being walked.
+// Layers of cellx exercise that, and used to crash checkDirty.
+func TestCheckDirtySharedFirstSubscriber(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+	start := [4]*alien.WriteableSignal[int]{
+		alien.Signal(rs, 1),
+		alien.Signal(rs, 2),
+		alien.Signal(rs, 3),
+		alien.Signal(rs, 4),
+	}
+	type layer struct{ a, b, c, d alien.Readable[int] }
+	current := layer{start[0], start[1], start[2], start[3]}
+	for range 10 {
+		m := current
+		current = layer{
+			a: alien.Computed(rs, func(oldValue int) int { return m.b.Value() }),
+			b: alien.Computed(rs, func(oldValue int) int { return m.a.Value() - m.c.Value() }),
+			c: alien.Computed(rs, func(oldValue int) int { return m.b.Value() + m.d.Value() }),
+			d: alien.Computed(rs, func(oldValue int) int { return m.c.Value() }),
+		}
+	}
+	var seen [4]int
+	for i, cell := range []alien.Readable[int]{current.a, current.b, current.c, current.d} {
+		alien.Effect(rs, func() error {
+			seen[i] = cell.Value()
+			return nil
+		})
+	}
+
+	expect := func(a, b, c, d int) [4]int {
+		for range 10 {
+			a, b, c, d = b, a-c, b+d, c
+		}
+		return [4]int{a, b, c, d}
+	}
+	assert.Equal(t, expect(1, 2, 3, 4), seen)
+	rs.Batch(func() {
+		start[0].SetValue(4)
+		start[1].SetValue(3)
+		start[2].SetValue(2)
+		start[3].SetValue(1)
+	})
+	assert.Equal(t, expect(4, 3, 2, 1), seen)
+}
+
+func TestTopologyUnchangedNestedComputedKeepsCheckingSiblings(t *testing.T) {
+	rs := alien.CreateReactiveSystem(func(from alien.SignalAware, err error) {
+		assert.FailNow(t, err.Error())
+	})
+
+	//     a
+	//   /   \
+	//  W     |
+	//  |     |
+	//  Y     Z
+	//   \   /
+	//     X
+	//     |
+	//     E
+	// W swallows the write, so Y stays pending and unchanged; X must still
+	// look at Z before deciding E is clean.
+	a := alien.Signal(rs, 1)
+	w := alien.Computed(rs, func(oldValue int) int { return a.Value() / 10 })
+	y := alien.Computed(rs, func(oldValue int) int { return w.Value() })
+	z := alien.Computed(rs, func(oldValue int) int { return a.Value() })
+	x := alien.Computed(rs, func(oldValue int) int { return y.Value() + z.Value() })
+
+	var seen []int
+	alien.Effect(rs, func() error {
+		seen = append(seen, x.Value())
+		return nil
+	})
+	a.SetValue(2)
+	a.SetValue(3)
+	assert.Equal(t, []int{1, 2, 3}, seen)
+}
@@ -0,0 +1,75 @@
+package alien
+
+import "time"
+
+// Tracer receives the lifecycle events of a ReactiveSystem. Events are
+// delivered synchronously on the goroutine driving the system, so
+// implementations should be cheap.
+type Tracer interface {
+	SignalWrite(node SignalAware)
+	BatchStart(depth int)
+	BatchEnd(depth int, elapsed time.Duration)
+	ComputedRecompute(node SignalAware, elapsed time.Duration, changed bool)
+	EffectRun(node SignalAware, elapsed time.Duration, err error)
+	Link(dep, sub SignalAware)
+	Unlink(dep, sub SignalAware)
+}
+
+// WithTracer installs a Tracer. It may be given several times, in which case
+// every tracer sees every event in the order they were installed.
+func WithTracer(tracer Tracer) SystemOption {
+	return func(rs *ReactiveSystem) {
+		switch existing := rs.tracer.(type) {
+		case nil:
+			rs.tracer = tracer
+		case multiTracer:
+			rs.tracer = append(existing, tracer)
+		default:
+			rs.tracer = multiTracer{existing, tracer}
+		}
+	}
+}
+
+type multiTracer []Tracer
+
+func (m multiTracer) SignalWrite(node SignalAware) {
+	for _, t := range m {
+		t.SignalWrite(node)
+	}
+}
+
+func (m multiTracer) BatchStart(depth int) {
+	for _, t := range m {
+		t.BatchStart(depth)
+	}
+}
+
+func (m multiTracer) BatchEnd(depth int, elapsed time.Duration) {
+	for _, t := range m {
+		t.BatchEnd(depth, elapsed)
+	}
+}
+
+func (m multiTracer) ComputedRecompute(node SignalAware, elapsed time.Duration, changed bool) {
+	for _, t := range m {
+		t.ComputedRecompute(node, elapsed, changed)
+	}
+}
+
+func (m multiTracer) EffectRun(node SignalAware, elapsed time.Duration, err error) {
+	for _, t := range m {
+		t.EffectRun(node, elapsed, err)
+	}
+}
+
+func (m multiTracer) Link(dep, sub SignalAware) {
+	for _, t := range m {
+		t.Link(dep, sub)
+	}
+}
+
+func (m multiTracer) Unlink(dep, sub SignalAware) {
+	for _, t := range m {
+		t.Unlink(dep, sub)
+	}
+}
@@ -12,6 +12,8 @@ const (
 	fPendingComputed
 	fPendingEffect
 	fEffectScope
+	fParallel
+	fRan
 	fPropagated subscriberFlags = fDirty | fPendingComputed | fPendingEffect
 )
 
@@ -27,4 +29,5 @@ type signal struct {
 	ref                            interface{}
 	flags                          subscriberFlags
 	deps, depsTail, subs, subsTail *link
+	info                           *nodeInfo
 }